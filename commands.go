@@ -0,0 +1,296 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"image/png"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/storage"
+	"fyne.io/fyne/v2/test"
+	"fyne.io/fyne/v2/theme"
+
+	"github.com/fyne-io/defyne/pkg/gui"
+)
+
+// commands run from the command line as `defyne <command> [arguments]`, without opening a window.
+var commands = map[string]func(args []string) error{
+	"check":     checkCommand,
+	"generate":  generateCommand,
+	"lint":      lintCommand,
+	"merge-gui": mergeCommand,
+	"render":    renderCommand,
+	"schema":    schemaCommand,
+}
+
+// runCommand runs the command named by the first argument, if there is one.
+// It returns false if the arguments should be used to open a project instead.
+func runCommand(args []string) bool {
+	if len(args) == 0 {
+		return false
+	}
+	cmd, ok := commands[args[0]]
+	if !ok {
+		return false
+	}
+
+	if err := cmd(args[1:]); err != nil {
+		fmt.Fprintln(os.Stderr, "Error:", err)
+		os.Exit(1)
+	}
+	return true
+}
+
+// schemaCommand writes the JSON Schema of the .gui.json format to a file, or to standard output.
+func schemaCommand(args []string) error {
+	flags := flag.NewFlagSet("schema", flag.ExitOnError)
+	flags.Usage = func() {
+		fmt.Fprintln(flags.Output(), "Usage: defyne schema [file]")
+		fmt.Fprintln(flags.Output(), "Writes the JSON Schema for .gui.json design files to the file, or standard output.")
+	}
+	_ = flags.Parse(args)
+	if flags.NArg() > 1 {
+		flags.Usage()
+		os.Exit(2)
+	}
+
+	var w io.Writer = os.Stdout
+	if flags.NArg() == 1 {
+		f, err := os.Create(flags.Arg(0))
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		w = f
+	}
+
+	return gui.ExportSchema(w)
+}
+
+// renderCommand draws a design to a PNG image, without opening a window.
+func renderCommand(args []string) error {
+	flags := flag.NewFlagSet("render", flag.ExitOnError)
+	out := flags.String("o", "", "the PNG file to write, defaults to the design file name with a .png extension")
+	size := flags.String("size", "", "the size to render at, like 800x600, defaults to the test size of the design")
+	variant := flags.String("theme", "light", "the theme variant to render with, light or dark")
+	flags.Usage = func() {
+		fmt.Fprintln(flags.Output(), "Usage: defyne render [options] file.gui.json")
+		fmt.Fprintln(flags.Output(), "Draws the design to a PNG image, without needing a display.")
+		flags.PrintDefaults()
+	}
+	files := parseFlags(flags, args)
+	if len(files) != 1 {
+		flags.Usage()
+		os.Exit(2)
+	}
+
+	var v fyne.ThemeVariant
+	switch *variant {
+	case "light":
+		v = theme.VariantLight
+	case "dark":
+		v = theme.VariantDark
+	default:
+		return fmt.Errorf("unknown theme %q, expected light or dark", *variant)
+	}
+
+	r, err := os.Open(files[0])
+	if err != nil {
+		return err
+	}
+	obj, meta, err := gui.DecodeObject(r)
+	_ = r.Close()
+	if err != nil {
+		return err
+	}
+
+	s := gui.TestSize(obj, meta)
+	if *size != "" {
+		if s, err = gui.ParseSize(*size); err != nil {
+			return err
+		}
+	}
+	if *out == "" {
+		*out = strings.TrimSuffix(strings.TrimSuffix(files[0], ".json"), ".gui") + ".png"
+	}
+
+	f, err := os.Create(*out)
+	if err != nil {
+		return err
+	}
+	err = png.Encode(f, gui.RenderImage(obj, s, v))
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
+
+// generateCommand regenerates the Go code and render test of every design in a project, it can be run from `//go:generate`.
+func generateCommand(args []string) error {
+	designs, err := projectDesigns("generate", args)
+	if err != nil {
+		return err
+	}
+
+	for _, design := range designs {
+		code, err := gui.GenerateGo(design)
+		if err != nil {
+			return fmt.Errorf("%s: %w", design, err)
+		}
+		if err = os.WriteFile(gui.GeneratedFile(design), code, 0644); err != nil {
+			return err
+		}
+		test, err := gui.GenerateGoTest(design)
+		if err != nil {
+			return fmt.Errorf("%s: %w", design, err)
+		}
+		if err = os.WriteFile(gui.GeneratedTestFile(design), test, 0644); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// checkCommand fails if any design of a project cannot be decoded or its generated Go code or test is out of date.
+func checkCommand(args []string) error {
+	designs, err := projectDesigns("check", args)
+	if err != nil {
+		return err
+	}
+
+	failed := 0
+	for _, design := range designs {
+		current, err := gui.IsGeneratedCurrent(design)
+		switch {
+		case err != nil:
+			fmt.Fprintf(os.Stderr, "%s: %s\n", design, err)
+		case !current:
+			fmt.Fprintf(os.Stderr, "%s: generated code or test is out of date, run defyne generate\n", design)
+		default:
+			continue
+		}
+		failed++
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d of %d designs failed the check", failed, len(designs))
+	}
+	return nil
+}
+
+// lintCommand checks every design of a project for common mistakes, such as buttons that show nothing,
+// and fails if any problems are found.
+func lintCommand(args []string) error {
+	designs, err := projectDesigns("lint", args)
+	if err != nil {
+		return err
+	}
+
+	test.NewApp() // the designs are laid out to measure them, which needs an app but not a display
+	problems := 0
+	for _, design := range designs {
+		found, err := gui.LintFile(design)
+		if err != nil {
+			return fmt.Errorf("%s: %w", design, err)
+		}
+		for _, p := range found {
+			fmt.Fprintf(os.Stderr, "%s: [%s] %s\n", design, p.Rule, p.Message)
+		}
+		problems += len(found)
+	}
+	if problems > 0 {
+		return fmt.Errorf("found %d problems in %d designs", problems, len(designs))
+	}
+	return nil
+}
+
+// projectDesigns parses the arguments of a command that works on a project directory, defaulting to the current one,
+// and returns the designs that it contains. The directory is used as the project root for resources and includes.
+func projectDesigns(name string, args []string) ([]string, error) {
+	flags := flag.NewFlagSet(name, flag.ExitOnError)
+	flags.Usage = func() {
+		fmt.Fprintf(flags.Output(), "Usage: defyne %s [directory]\n", name)
+		switch name {
+		case "check":
+			fmt.Fprintln(flags.Output(), "Fails if a design cannot be decoded or its generated .gui.go file is out of date.")
+		case "lint":
+			fmt.Fprintln(flags.Output(), "Checks every .gui.json design in the project for common mistakes, the rules are:")
+			for _, rule := range gui.LintRules {
+				fmt.Fprintf(flags.Output(), "  %-15s %s\n", rule.Name, rule.Description)
+			}
+		default:
+			fmt.Fprintln(flags.Output(), "Regenerates the .gui.go file of every .gui.json design in the project.")
+		}
+	}
+	dirs := parseFlags(flags, args)
+	if len(dirs) > 1 {
+		flags.Usage()
+		os.Exit(2)
+	}
+
+	dir := "."
+	if len(dirs) == 1 {
+		dir = dirs[0]
+	}
+	root, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, err
+	}
+	gui.SetProjectRoot(storage.NewFileURI(root))
+
+	return gui.FindDesigns(dir)
+}
+
+// mergeCommand is a git merge driver for designs, the result is written over the ours file.
+// It fails if the merge left conflicts, so that git reports the file as conflicted.
+func mergeCommand(args []string) error {
+	flags := flag.NewFlagSet("merge-gui", flag.ExitOnError)
+	flags.Usage = func() {
+		fmt.Fprintln(flags.Output(), "Usage: defyne merge-gui base ours theirs")
+		fmt.Fprintln(flags.Output(), "Merges the changes to a design and writes the result to the ours file, for use as a git merge driver.")
+	}
+	files := parseFlags(flags, args)
+	if len(files) != 3 {
+		flags.Usage()
+		os.Exit(2)
+	}
+
+	var data [3][]byte
+	for i, file := range files {
+		content, err := os.ReadFile(file)
+		if err != nil {
+			return err
+		}
+		data[i] = content
+	}
+	merged, conflicts, err := gui.MergeDocuments(data[0], data[1], data[2])
+	if err != nil {
+		return err
+	}
+	if err = os.WriteFile(files[1], merged, 0644); err != nil {
+		return err
+	}
+
+	if conflicts > 0 {
+		return fmt.Errorf("%d conflicts merging %s", conflicts, files[1])
+	}
+	return nil
+}
+
+// parseFlags parses the flags of a command, which may come before or after its other arguments,
+// and returns the other arguments.
+func parseFlags(flags *flag.FlagSet, args []string) []string {
+	var rest []string
+	for {
+		_ = flags.Parse(args)
+		if flags.NArg() == 0 {
+			return rest
+		}
+
+		rest = append(rest, flags.Arg(0))
+		args = flags.Args()[1:]
+	}
+}
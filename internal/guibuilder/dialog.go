@@ -0,0 +1,118 @@
+package guibuilder
+
+import (
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/canvas"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/layout"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+
+	"github.com/fyne-io/defyne/pkg/gui"
+)
+
+const showInWindow = "Window"
+
+// dialogItems returns the form items that pick where the design is shown, and edit its dialog if it has one.
+func (b *Builder) dialogItems(setItems func([]*widget.FormItem)) []*widget.FormItem {
+	dlg := gui.DialogFor(b.root, b.meta)
+	update := func() {
+		gui.SetDialog(b.root, b.meta, dlg)
+		b.refreshFrame()
+	}
+
+	showIn := widget.NewSelect(append([]string{showInWindow}, gui.DialogTypes...), nil)
+	showIn.Selected = showInWindow
+	if dlg != nil {
+		showIn.Selected = dlg.Type
+	}
+	showIn.OnChanged = func(typ string) {
+		switch {
+		case typ == showInWindow:
+			dlg = nil
+		case typ == gui.DialogForm && !isForm(b.root):
+			showIn.SetSelected(showInWindow)
+			return
+		case dlg == nil:
+			dlg = &gui.Dialog{Type: typ}
+			gui.SetWindow(b.root, b.meta, nil) // a dialog design is not shown in its own window
+		default:
+			dlg.Type = typ
+		}
+		update()
+		setItems(b.dialogItems(setItems))
+	}
+
+	items := []*widget.FormItem{widget.NewFormItem("Show In", showIn)}
+	if dlg == nil {
+		return append(items, widget.NewFormItem("Window", widget.NewButton("Edit Window...", b.showWindowEditor)))
+	}
+
+	entry := func(label, text string, set func(string)) *widget.FormItem {
+		e := widget.NewEntry()
+		e.SetText(text)
+		e.OnChanged = func(s string) {
+			set(s)
+			update()
+		}
+		return widget.NewFormItem(label, e)
+	}
+	action := func(label, name string) *widget.FormItem {
+		return entry(label, dlg.Actions[name], func(s string) {
+			if dlg.Actions == nil {
+				dlg.Actions = make(map[string]string)
+			}
+			if s == "" {
+				delete(dlg.Actions, name)
+			} else {
+				dlg.Actions[name] = s
+			}
+		})
+	}
+
+	items = append(items,
+		entry("Dialog Title", dlg.Title, func(s string) { dlg.Title = s }),
+		entry("Dismiss Text", dlg.Dismiss, func(s string) { dlg.Dismiss = s }))
+	if dlg.Type != gui.DialogCustom {
+		items = append(items,
+			entry("Confirm Text", dlg.Confirm, func(s string) { dlg.Confirm = s }),
+			action("On Confirmed", "OnConfirmed"))
+	}
+	return append(items, action("On Closed", "OnClosed"))
+}
+
+// refreshFrame shows the design as window content, or inside a mock of the dialog it is shown in.
+func (b *Builder) refreshFrame() {
+	if b.frame == nil {
+		return
+	}
+
+	if dlg := gui.DialogFor(b.root, b.meta); dlg != nil {
+		b.frame.Objects = []fyne.CanvasObject{newDialogFrame(b.root, dlg)}
+	} else {
+		b.frame.Objects = []fyne.CanvasObject{b.root}
+	}
+	b.frame.Refresh()
+}
+
+// newDialogFrame returns the content of a design surrounded by the title and buttons of its dialog.
+func newDialogFrame(content fyne.CanvasObject, dlg *gui.Dialog) fyne.CanvasObject {
+	title := widget.NewLabelWithStyle(dlg.Title, fyne.TextAlignLeading, fyne.TextStyle{Bold: true})
+	buttons := container.NewHBox(layout.NewSpacer(), widget.NewButton(dlg.DismissText(), nil))
+	if dlg.Type != gui.DialogCustom {
+		confirm := widget.NewButton(dlg.ConfirmText(), nil)
+		confirm.Importance = widget.HighImportance
+		buttons.Add(confirm)
+	}
+	buttons.Add(layout.NewSpacer())
+
+	bg := canvas.NewRectangle(theme.Color(theme.ColorNameOverlayBackground))
+	bg.CornerRadius = theme.InputRadiusSize()
+	box := container.NewStack(bg, container.NewPadded(container.NewBorder(title, buttons, nil, nil, content)))
+	return container.NewCenter(box)
+}
+
+func isForm(o fyne.CanvasObject) bool {
+	_, ok := o.(*widget.Form)
+	return ok
+}
@@ -0,0 +1,116 @@
+package guibuilder
+
+import (
+	"bytes"
+	"io"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/storage"
+
+	"github.com/fyne-io/defyne/pkg/gui"
+)
+
+// defaultLocale is shown in the locale list to run the preview using the locale of the system.
+const defaultLocale = "System Default"
+
+// sourceLocale is the locale of the text in a design, the source strings are written to a translation file for it.
+const sourceLocale = "en"
+
+// locales returns the locales of the translation files next to the design, such as "fr" for "main.fr.json".
+func (b *Builder) locales() []string {
+	var ret []string
+	for _, res := range b.translations() {
+		name := strings.TrimSuffix(res.Name(), ".json")
+		if i := strings.LastIndex(name, "."); i >= 0 {
+			name = name[i+1:]
+		}
+
+		found := false
+		for _, l := range ret {
+			if l == name {
+				found = true
+				break
+			}
+		}
+		if !found {
+			ret = append(ret, name)
+		}
+	}
+	return ret
+}
+
+// saveTranslations writes the translated text of the design to "translations/<name>.en.json" next to the design.
+// Nothing is written if no text is translated.
+func (b *Builder) saveTranslations(name string) error {
+	var buf bytes.Buffer
+	if err := gui.ExportTranslations(b.root, b.meta, &buf); err != nil {
+		return err
+	}
+	if buf.String() == "{}\n" {
+		return nil
+	}
+
+	dir, err := b.translationsDir()
+	if err != nil {
+		return err
+	}
+	if ok, _ := storage.Exists(dir); !ok {
+		if err = storage.CreateListable(dir); err != nil {
+			return err
+		}
+	}
+	u, err := storage.Child(dir, name+"."+sourceLocale+".json")
+	if err != nil {
+		return err
+	}
+
+	w, err := storage.Writer(u)
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(buf.Bytes())
+	_ = w.Close()
+	return err
+}
+
+// translations returns the translation files in the "translations" directory next to the design.
+func (b *Builder) translations() []fyne.Resource {
+	dir, err := b.translationsDir()
+	if err != nil {
+		return nil
+	}
+	list, err := storage.List(dir)
+	if err != nil {
+		return nil
+	}
+
+	var ret []fyne.Resource
+	for _, u := range list {
+		if u.Extension() != ".json" {
+			continue
+		}
+
+		r, err := storage.Reader(u)
+		if err != nil {
+			fyne.LogError("Failed to read translations", err)
+			continue
+		}
+		data, err := io.ReadAll(r)
+		_ = r.Close()
+		if err != nil {
+			fyne.LogError("Failed to read translations", err)
+			continue
+		}
+		ret = append(ret, fyne.NewStaticResource(u.Name(), data))
+	}
+	return ret
+}
+
+func (b *Builder) translationsDir() (fyne.URI, error) {
+	dir, err := storage.Parent(b.uri)
+	if err != nil {
+		return nil, err
+	}
+	return storage.Child(dir, "translations")
+}
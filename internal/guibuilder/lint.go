@@ -0,0 +1,45 @@
+package guibuilder
+
+import (
+	"path/filepath"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+
+	"github.com/fyne-io/defyne/pkg/gui"
+)
+
+// showLint checks the design with the lint rules and lists the problems, tapping one selects its object.
+func (b *Builder) showLint() {
+	var sources []string
+	if b.uri.Scheme() == "file" {
+		sources, _ = gui.PackageSources(filepath.Dir(b.uri.Path()))
+	}
+	problems := gui.Lint(b.root, b.meta, sources...)
+	if len(problems) == 0 {
+		dialog.ShowInformation("Lint", "No problems found in this design.", b.win)
+		return
+	}
+
+	var d dialog.Dialog
+	list := widget.NewList(
+		func() int {
+			return len(problems)
+		},
+		func() fyne.CanvasObject {
+			return widget.NewLabel("Problem")
+		},
+		func(id widget.ListItemID, o fyne.CanvasObject) {
+			o.(*widget.Label).SetText("[" + problems[id].Rule + "] " + problems[id].Message)
+		})
+	list.OnSelected = func(id widget.ListItemID) {
+		d.Hide()
+		b.overlay.highlight(problems[id].Object)
+		b.choose(problems[id].Object)
+	}
+
+	d = dialog.NewCustom("Lint", "Close", list, b.win)
+	d.Resize(fyne.NewSize(560, 320))
+	d.Show()
+}
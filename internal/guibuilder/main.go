@@ -1,10 +1,12 @@
 package guibuilder
 
 import (
+	"fmt"
 	"os"
 	"os/exec"
 	"path/filepath"
 	"reflect"
+	"strconv"
 	"strings"
 
 	"fyne.io/fyne/v2"
@@ -29,9 +31,12 @@ var (
 // Builder is a simple type handle for a GUI builder instance.
 type Builder struct {
 	root, current fyne.CanvasObject
+	frame         *fyne.Container
+	overlay       *overlay
 	uri           fyne.URI
 	win           fyne.Window
 	meta          map[fyne.CanvasObject]map[string]string
+	locale        string
 }
 
 // NewBuilder returns an instance of the GUI builder for the specified URI.
@@ -48,7 +53,8 @@ func NewBuilder(u fyne.URI, win fyne.Window) *Builder {
 	if r == nil {
 		obj = previewUI()
 	} else {
-		obj, meta, err = gui.DecodeObject(r)
+		// load as much of the design as possible, any invalid parts are skipped and listed in the error
+		obj, meta, err = gui.DecodeObjectLenient(r)
 		if err != nil {
 			dialog.ShowError(err, win)
 		}
@@ -70,6 +76,11 @@ func (b *Builder) MakeUI() fyne.CanvasObject {
 	return b.buildUI(b.root)
 }
 
+// SetIncludeOpener sets the function that is called to edit a design that is included by another one.
+func SetIncludeOpener(open func(fyne.URI)) {
+	guidefs.OpenInclude = open
+}
+
 // Run generates a go main function and runs it so we can preview the UI in a real app.
 func (b *Builder) Run() {
 	path := filepath.Join(os.TempDir(), "fynebuilder")
@@ -84,7 +95,7 @@ func (b *Builder) Run() {
 		fyne.LogError("Failed get storage writer", err)
 		return
 	}
-	err = gui.ExportGoPreview(b.root, b.meta, w)
+	err = gui.ExportGoPreviewWithTranslations(b.root, b.meta, b.translations(), w)
 	if err != nil {
 		fyne.LogError("Failed to export go preview", err)
 		return
@@ -101,6 +112,9 @@ func (b *Builder) Run() {
 	cmd.Stdout = os.Stdout
 	cmd.Run()
 	cmd = exec.Command("go", "run", ".")
+	if b.locale != "" {
+		cmd.Env = append(os.Environ(), "LANGUAGE="+b.locale, "LC_ALL="+b.locale)
+	}
 	cmd.Stderr = os.Stderr
 	cmd.Stdout = os.Stdout
 	cmd.Start()
@@ -122,13 +136,22 @@ func (b *Builder) Save() error {
 	if err != nil {
 		return err
 	}
-	err = gui.ExportGo(b.root, b.meta, name, w)
+	err = gui.ExportDesignGo(b.root, b.meta, name, w)
 	if err != nil {
 		return err
 	}
 
 	_ = w.Close()
 
+	err = b.saveTranslations(name)
+	if err != nil {
+		return err
+	}
+	err = b.saveTest(dir, name)
+	if err != nil {
+		return err
+	}
+
 	w, err = storage.Writer(b.uri)
 	if err != nil {
 		return err
@@ -226,13 +249,55 @@ func (b *Builder) buildLibrary() fyne.CanvasObject {
 }
 
 func (b *Builder) buildUI(content fyne.CanvasObject) fyne.CanvasObject {
-	wrap := container.NewStack(b.root, newOverlay(b))
+	b.frame = container.NewStack()
+	b.refreshFrame()
+	b.overlay = newOverlay(b)
+	wrap := container.NewStack(b.frame, b.overlay)
 
 	widName = widget.NewEntry()
 	widName.Validator = validation.NewRegexp("^$|^[a-zA-Z_][a-zA-Z0-9_]*$", "Invalid variable name")
+	locale := widget.NewSelect(append([]string{defaultLocale}, b.locales()...), func(l string) {
+		if l == defaultLocale {
+			l = ""
+		}
+		b.locale = l
+	})
+	locale.Selected = defaultLocale
+	if b.locale != "" {
+		locale.Selected = b.locale
+	}
+	testSize := widget.NewEntry()
+	size := gui.TestSize(b.root, b.meta)
+	testSize.SetText(fmt.Sprintf("%gx%g", size.Width, size.Height))
+	testSize.Validator = validation.NewRegexp(`^[0-9]+(\.[0-9]+)?x[0-9]+(\.[0-9]+)?$`, "Size must be like 400x300")
+	testSize.OnChanged = func(s string) {
+		if testSize.Validate() != nil {
+			return
+		}
+
+		width, height, _ := strings.Cut(s, "x")
+		w, _ := strconv.ParseFloat(width, 32)
+		h, _ := strconv.ParseFloat(height, 32)
+		gui.SetTestSize(b.root, b.meta, fyne.NewSize(float32(w), float32(h)))
+	}
+	handlers := widget.NewCheck("Call handler methods", func(on bool) {
+		gui.SetUsesHandlers(b.root, b.meta, on)
+	})
+	handlers.Checked = gui.UsesHandlers(b.root, b.meta)
 	paletteList = container.NewVBox()
-	palette := container.NewBorder(
-		widget.NewForm(widget.NewFormItem("Variable", widName)), nil, nil, nil,
+	design := widget.NewForm(widget.NewFormItem("Variable", widName), widget.NewFormItem("Preview Locale", locale),
+		widget.NewFormItem("Test Size", testSize), widget.NewFormItem("Actions", handlers),
+		widget.NewFormItem("Lint", widget.NewButton("Check Design...", b.showLint)))
+	fixed := design.Items
+	var setDialogItems func([]*widget.FormItem)
+	setDialogItems = func(items []*widget.FormItem) {
+		design.Items = nil
+		design.Refresh()
+		design.Items = append(append([]*widget.FormItem{}, fixed...), items...)
+		design.Refresh()
+	}
+	setDialogItems(b.dialogItems(setDialogItems))
+	palette := container.NewBorder(design, nil, nil, nil,
 		container.NewGridWithRows(2, widget.NewCard("Properties", "",
 			container.NewVScroll(paletteList)),
 			widget.NewCard("Component List", "", b.buildLibrary()),
@@ -256,13 +321,12 @@ func (b *Builder) choose(o fyne.CanvasObject) {
 	}
 	widName.SetText(name)
 
-	props := b.meta[o]
-	if props == nil {
-		props = make(map[string]string)
+	if b.meta[o] == nil {
+		b.meta[o] = make(map[string]string)
 	}
 	nameItem := widget.NewFormItem("Type", widget.NewLabel(gui.NameOf(o)))
 	editForm = widget.NewForm()
-	items := gui.EditorFor(o, props, func(items []*widget.FormItem) {
+	items := gui.EditorForDesign(o, b.meta, func(items []*widget.FormItem) {
 		editForm.Items = nil
 		editForm.Refresh()
 		editForm.Items = append([]*widget.FormItem{nameItem}, items...)
@@ -270,7 +334,6 @@ func (b *Builder) choose(o fyne.CanvasObject) {
 	}, nil)
 
 	items = append([]*widget.FormItem{nameItem}, items...)
-	b.meta[o] = props
 
 	editForm.Items = items
 	remove := widget.NewButton("Remove", func() {
@@ -280,7 +343,7 @@ func (b *Builder) choose(o fyne.CanvasObject) {
 		}
 
 		parent.Remove(b.current)
-		parent.Refresh()
+		guidefs.UpdateLayout(parent, b.meta) // clear any references to the removed object
 		b.choose(parent)
 	})
 	paletteList.Objects = []fyne.CanvasObject{editForm, remove}
@@ -42,16 +42,29 @@ func (o *overlay) CreateRenderer() fyne.WidgetRenderer {
 func (o *overlay) Tapped(pe *fyne.PointEvent) {
 	rootPos := fyne.CurrentApp().Driver().AbsolutePositionForObject(o.b.root)
 	pos := pe.AbsolutePosition.Subtract(rootPos)
+	size := o.b.root.Size()
+	if pos.X < 0 || pos.Y < 0 || pos.X >= size.Width || pos.Y >= size.Height {
+		return // tapped the frame of a dialog
+	}
 	obj := findObject(o.b.root, pos)
+	if obj == nil {
+		return
+	}
 
+	o.highlight(obj)
+	o.b.choose(obj)
+}
+
+// highlight moves the selection indicator over an object of the design.
+func (o *overlay) highlight(obj fyne.CanvasObject) {
 	// TODO update when an item is removed, inserted, or if the UI resizes
 	o.indicator.StrokeColor = theme.Color(theme.ColorNamePrimary)
 	objAbsPos := fyne.CurrentApp().Driver().AbsolutePositionForObject(obj)
-	objPos := objAbsPos.Subtract(rootPos)
+	// the design may be framed by a dialog, so position relative to the overlay rather than the root
+	objPos := objAbsPos.Subtract(fyne.CurrentApp().Driver().AbsolutePositionForObject(o))
 	o.indicator.Move(objPos)
 	o.indicator.Resize(obj.Size())
-
-	o.b.choose(obj)
+	o.indicator.Refresh()
 }
 
 func findObject(o fyne.CanvasObject, p fyne.Position) fyne.CanvasObject {
@@ -0,0 +1,24 @@
+package guibuilder
+
+import (
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/storage"
+
+	"github.com/fyne-io/defyne/pkg/gui"
+)
+
+// saveTest writes the generated render test of the design to "<name>_gui_test.go" in dir.
+// The snapshot is kept when the design changes, so that the test reports the difference until it is recorded again.
+func (b *Builder) saveTest(dir fyne.URI, name string) error {
+	testURI, err := storage.Child(dir, name+"_gui_test.go")
+	if err != nil {
+		return err
+	}
+	w, err := storage.Writer(testURI)
+	if err != nil {
+		return err
+	}
+	err = gui.ExportGoTest(b.root, b.meta, name, w)
+	_ = w.Close()
+	return err
+}
@@ -0,0 +1,269 @@
+package guibuilder
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/data/validation"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+
+	"github.com/fyne-io/defyne/pkg/gui"
+)
+
+// showWindowEditor opens a dialog that edits the window of the design, including its main menu.
+func (b *Builder) showWindowEditor() {
+	win := gui.WindowFor(b.root, b.meta)
+	if win == nil {
+		win = &gui.Window{}
+	}
+	save := func() {
+		gui.SetWindow(b.root, b.meta, win)
+	}
+
+	title := widget.NewEntry()
+	title.SetText(win.Title)
+	title.OnChanged = func(s string) {
+		win.Title = s
+		save()
+	}
+	size := widget.NewEntry()
+	size.SetPlaceHolder("640x480")
+	if win.Size != nil {
+		size.SetText(fmt.Sprintf("%gx%g", win.Size.Width, win.Size.Height))
+	}
+	size.Validator = validation.NewRegexp(`^$|^[0-9]+(\.[0-9]+)?x[0-9]+(\.[0-9]+)?$`, "Size must be like 640x480")
+	size.OnChanged = func(s string) {
+		if size.Validate() != nil {
+			return
+		}
+
+		win.Size = nil
+		if s != "" {
+			width, height, _ := strings.Cut(s, "x")
+			w, _ := strconv.ParseFloat(width, 32)
+			h, _ := strconv.ParseFloat(height, 32)
+			win.Size = &fyne.Size{Width: float32(w), Height: float32(h)}
+		}
+		save()
+	}
+	fixed := widget.NewCheck("", func(on bool) {
+		win.FixedSize = on
+		save()
+	})
+	fixed.Checked = win.FixedSize
+	master := widget.NewCheck("", func(on bool) {
+		win.Master = on
+		save()
+	})
+	master.Checked = win.Master
+
+	form := widget.NewForm(widget.NewFormItem("Title", title), widget.NewFormItem("Size", size),
+		widget.NewFormItem("Fixed Size", fixed), widget.NewFormItem("Master", master))
+	content := container.NewBorder(form, nil, nil, nil, newMenuEditor(win, save))
+	d := dialog.NewCustom("Window", "Done", content, b.win)
+	d.Resize(fyne.NewSize(560, 520))
+	d.Show()
+}
+
+// newMenuEditor returns a tree of the main menu of a window, with tools to add, remove and edit its items.
+// Tree nodes are identified by the path of indexes to the menu or item, such as "0/2".
+func newMenuEditor(win *gui.Window, save func()) fyne.CanvasObject {
+	menuAt := func(id widget.TreeNodeID) (*gui.Menu, []int) {
+		var path []int
+		for _, part := range strings.Split(id, "/") {
+			i, err := strconv.Atoi(part)
+			if err != nil {
+				return nil, nil
+			}
+			path = append(path, i)
+		}
+		if len(path) == 0 || path[0] >= len(win.MainMenu) {
+			return nil, nil
+		}
+		return win.MainMenu[path[0]], path[1:]
+	}
+	itemAt := func(id widget.TreeNodeID) *gui.MenuItem {
+		menu, path := menuAt(id)
+		if menu == nil || len(path) == 0 {
+			return nil
+		}
+
+		items := menu.Items
+		var item *gui.MenuItem
+		for _, i := range path {
+			if i >= len(items) {
+				return nil
+			}
+			item = items[i]
+			items = item.Items
+		}
+		return item
+	}
+	childCount := func(id widget.TreeNodeID) int {
+		if id == "" {
+			return len(win.MainMenu)
+		}
+		if item := itemAt(id); item != nil {
+			return len(item.Items)
+		}
+		if menu, _ := menuAt(id); menu != nil {
+			return len(menu.Items)
+		}
+		return 0
+	}
+
+	tree := widget.NewTree(func(id widget.TreeNodeID) []widget.TreeNodeID {
+		ids := make([]widget.TreeNodeID, childCount(id))
+		for i := range ids {
+			if id == "" {
+				ids[i] = strconv.Itoa(i)
+			} else {
+				ids[i] = id + "/" + strconv.Itoa(i)
+			}
+		}
+		return ids
+	}, func(id widget.TreeNodeID) bool {
+		if id == "" || !strings.Contains(id, "/") {
+			return true
+		}
+		item := itemAt(id)
+		return item != nil && len(item.Items) > 0
+	}, func(bool) fyne.CanvasObject {
+		return widget.NewLabel("Template Menu Item")
+	}, func(id widget.TreeNodeID, _ bool, o fyne.CanvasObject) {
+		text := ""
+		if item := itemAt(id); item != nil {
+			text = item.Label
+			if item.Separator {
+				text = "———"
+			} else if item.Shortcut != "" {
+				text += " (" + item.Shortcut + ")"
+			}
+		} else if menu, _ := menuAt(id); menu != nil {
+			text = menu.Label
+		}
+		o.(*widget.Label).SetText(text)
+	})
+
+	selected := ""
+	label, shortcut, action := widget.NewEntry(), widget.NewEntry(), widget.NewEntry()
+	shortcut.SetPlaceHolder("Shortcut+S")
+	shortcut.Validator = func(s string) error {
+		if s == "" {
+			return nil
+		}
+		_, err := gui.ParseShortcut(s)
+		return err
+	}
+	action.SetPlaceHolder("func() {}")
+	details := widget.NewForm(widget.NewFormItem("Label", label), widget.NewFormItem("Shortcut", shortcut),
+		widget.NewFormItem("Action", action))
+	details.Hide()
+	changed := func() {
+		save()
+		tree.Refresh()
+	}
+	label.OnChanged = func(s string) {
+		if item := itemAt(selected); item != nil {
+			item.Label = s
+		} else if menu, _ := menuAt(selected); menu != nil {
+			menu.Label = s
+		}
+		changed()
+	}
+	shortcut.OnChanged = func(s string) {
+		if item := itemAt(selected); item != nil && shortcut.Validate() == nil {
+			item.Shortcut = s
+			changed()
+		}
+	}
+	action.OnChanged = func(s string) {
+		if item := itemAt(selected); item != nil {
+			item.Action = s
+			changed()
+		}
+	}
+	tree.OnSelected = func(id widget.TreeNodeID) {
+		selected = id
+		item := itemAt(id)
+		if item != nil && item.Separator {
+			details.Hide()
+			return
+		}
+
+		label.SetText("")
+		shortcut.SetText("")
+		action.SetText("")
+		if item != nil {
+			label.SetText(item.Label)
+			shortcut.SetText(item.Shortcut)
+			action.SetText(item.Action)
+			shortcut.Enable()
+			action.Enable()
+		} else if menu, _ := menuAt(id); menu != nil {
+			label.SetText(menu.Label)
+			shortcut.Disable()
+			action.Disable()
+		}
+		details.Show()
+	}
+
+	// add puts a new item in the selected menu or submenu, or next to the selected item
+	add := func(item *gui.MenuItem) {
+		target := selected
+		if parent := itemAt(target); parent != nil && len(parent.Items) == 0 {
+			target = target[:strings.LastIndex(target, "/")]
+		}
+
+		if parent := itemAt(target); parent != nil {
+			parent.Items = append(parent.Items, item)
+		} else if menu, _ := menuAt(target); menu != nil {
+			menu.Items = append(menu.Items, item)
+		} else {
+			return
+		}
+		tree.OpenBranch(target)
+		changed()
+	}
+	remove := func() {
+		if selected == "" {
+			return
+		}
+		cut := strings.LastIndex(selected, "/")
+		i, _ := strconv.Atoi(selected[cut+1:])
+		if cut < 0 {
+			win.MainMenu = append(win.MainMenu[:i], win.MainMenu[i+1:]...)
+		} else if parent := itemAt(selected[:cut]); parent != nil {
+			parent.Items = append(parent.Items[:i], parent.Items[i+1:]...)
+		} else if menu, _ := menuAt(selected[:cut]); menu != nil {
+			menu.Items = append(menu.Items[:i], menu.Items[i+1:]...)
+		}
+
+		tree.UnselectAll()
+		selected = ""
+		details.Hide()
+		changed()
+	}
+
+	tools := container.NewHBox(
+		widget.NewButtonWithIcon("Menu", theme.ContentAddIcon(), func() {
+			win.MainMenu = append(win.MainMenu, &gui.Menu{Label: fmt.Sprintf("Menu %d", len(win.MainMenu)+1)})
+			changed()
+		}),
+		widget.NewButtonWithIcon("Item", theme.ContentAddIcon(), func() {
+			add(&gui.MenuItem{Label: "Item"})
+		}),
+		widget.NewButtonWithIcon("Separator", theme.ContentAddIcon(), func() {
+			add(&gui.MenuItem{Separator: true})
+		}),
+		widget.NewButtonWithIcon("Submenu", theme.ContentAddIcon(), func() {
+			add(&gui.MenuItem{Label: "Submenu", Items: []*gui.MenuItem{{Label: "Item"}}})
+		}),
+		widget.NewButtonWithIcon("", theme.DeleteIcon(), remove))
+	return container.NewBorder(widget.NewLabel("Main Menu"), container.NewVBox(details, tools), nil, nil, tree)
+}
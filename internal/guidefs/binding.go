@@ -0,0 +1,145 @@
+package guidefs
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/data/validation"
+	"fyne.io/fyne/v2/widget"
+)
+
+// Bindings maps widget types to the data binding that can be used for their main value
+var Bindings = map[string]bindingInfo{
+	"*widget.Check": {
+		Type: "Bool",
+		Create: func(obj fyne.CanvasObject, props map[string]string, bind string) string {
+			return fmt.Sprintf("widget.NewCheckWithData(%s, %s)", TextGoString(props, "Text", obj.(*widget.Check).Text), bind)
+		},
+		Value: func(obj fyne.CanvasObject) string {
+			if !obj.(*widget.Check).Checked {
+				return ""
+			}
+			return "true"
+		},
+	},
+	"*widget.Entry": {
+		Type: "String",
+		Create: func(_ fyne.CanvasObject, _ map[string]string, bind string) string {
+			return fmt.Sprintf("widget.NewEntryWithData(%s)", bind)
+		},
+		Value: func(obj fyne.CanvasObject) string {
+			return stringValue(obj.(*widget.Entry).Text)
+		},
+	},
+	"*widget.Label": {
+		Type: "String",
+		Create: func(_ fyne.CanvasObject, _ map[string]string, bind string) string {
+			return fmt.Sprintf("widget.NewLabelWithData(%s)", bind)
+		},
+		Value: func(obj fyne.CanvasObject) string {
+			return stringValue(obj.(*widget.Label).Text)
+		},
+	},
+	"*widget.ProgressBar": {
+		Type: "Float",
+		Create: func(_ fyne.CanvasObject, _ map[string]string, bind string) string {
+			return fmt.Sprintf("widget.NewProgressBarWithData(%s)", bind)
+		},
+		Value: func(obj fyne.CanvasObject) string {
+			return floatValue(obj.(*widget.ProgressBar).Value)
+		},
+	},
+	"*widget.Slider": {
+		Type: "Float",
+		Create: func(obj fyne.CanvasObject, _ map[string]string, bind string) string {
+			s := obj.(*widget.Slider)
+			return fmt.Sprintf("widget.NewSliderWithData(%s, %s, %s)",
+				strconv.FormatFloat(s.Min, 'f', -1, 64), strconv.FormatFloat(s.Max, 'f', -1, 64), bind)
+		},
+		Value: func(obj fyne.CanvasObject) string {
+			return floatValue(obj.(*widget.Slider).Value)
+		},
+	},
+}
+
+type bindingInfo struct {
+	// Type is the name of the binding type in the binding package, such as "String"
+	Type string
+	// Create returns the Go code for the widget bound to the named binding
+	Create func(obj fyne.CanvasObject, props map[string]string, bind string) string
+	// Value returns the Go code for the current value of the widget, or "" if it is the zero value
+	Value func(obj fyne.CanvasObject) string
+}
+
+// NewBindingFormItem returns a form item for editing the name of the binding used by the object.
+// Names that the generated code could not declare, as reported by `BindingConflict`, are not accepted.
+// The result is nil if the type does not support data binding.
+func NewBindingFormItem(obj fyne.CanvasObject, meta map[fyne.CanvasObject]map[string]string, onchanged func()) *widget.FormItem {
+	info, ok := Bindings[reflect.TypeOf(obj).String()]
+	if !ok {
+		return nil
+	}
+
+	props := meta[obj]
+	bind := widget.NewEntry()
+	bind.SetPlaceHolder("(Not bound)")
+	bind.SetText(props["binding"])
+	validName := validation.NewRegexp("^$|^[a-zA-Z_][a-zA-Z0-9_]*$", "Invalid variable name")
+	bind.Validator = func(s string) error {
+		if err := validName(s); err != nil || s == "" {
+			return err
+		}
+		return BindingConflict(obj, s, meta)
+	}
+	bind.OnChanged = func(s string) {
+		if bind.Validate() != nil {
+			return
+		}
+
+		if s == "" {
+			delete(props, "binding")
+		} else {
+			props["binding"] = s
+		}
+		onchanged()
+	}
+
+	item := widget.NewFormItem("Binding", bind)
+	item.HintText = "binding." + info.Type
+	return item
+}
+
+// BindingConflict returns an error if the generated code could not declare the named binding for the object,
+// because an object of the design has the same name or binds it to a different type of value.
+func BindingConflict(obj fyne.CanvasObject, name string, meta map[fyne.CanvasObject]map[string]string) error {
+	kind := Bindings[reflect.TypeOf(obj).String()].Type
+	for o, props := range meta {
+		if props["name"] == name {
+			return fmt.Errorf("%s is the name of an object", name)
+		}
+		if o == obj || props["binding"] != name {
+			continue
+		}
+
+		if other := Bindings[reflect.TypeOf(o).String()].Type; other != kind {
+			return fmt.Errorf("%s is bound to a %s elsewhere", name, other)
+		}
+	}
+	return nil
+}
+
+func floatValue(f float64) string {
+	if f == 0 {
+		return ""
+	}
+	return strconv.FormatFloat(f, 'f', -1, 64)
+}
+
+func stringValue(s string) string {
+	if s == "" {
+		return ""
+	}
+	return "\"" + escapeLabel(s) + "\""
+}
@@ -0,0 +1,115 @@
+package guidefs
+
+import (
+	"image/color"
+	"reflect"
+	"sort"
+	"strconv"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/theme"
+)
+
+// ThemeColorNames is a sorted list of the color names provided by the theme
+var ThemeColorNames []string
+
+// themeColorConsts maps theme color names to the name of their constant in the theme package
+var themeColorConsts = map[fyne.ThemeColorName]string{
+	theme.ColorNameBackground:          "ColorNameBackground",
+	theme.ColorNameButton:              "ColorNameButton",
+	theme.ColorNameDisabledButton:      "ColorNameDisabledButton",
+	theme.ColorNameDisabled:            "ColorNameDisabled",
+	theme.ColorNameError:               "ColorNameError",
+	theme.ColorNameFocus:               "ColorNameFocus",
+	theme.ColorNameForeground:          "ColorNameForeground",
+	theme.ColorNameForegroundOnError:   "ColorNameForegroundOnError",
+	theme.ColorNameForegroundOnPrimary: "ColorNameForegroundOnPrimary",
+	theme.ColorNameForegroundOnSuccess: "ColorNameForegroundOnSuccess",
+	theme.ColorNameForegroundOnWarning: "ColorNameForegroundOnWarning",
+	theme.ColorNameHeaderBackground:    "ColorNameHeaderBackground",
+	theme.ColorNameHover:               "ColorNameHover",
+	theme.ColorNameHyperlink:           "ColorNameHyperlink",
+	theme.ColorNameInputBackground:     "ColorNameInputBackground",
+	theme.ColorNameInputBorder:         "ColorNameInputBorder",
+	theme.ColorNameMenuBackground:      "ColorNameMenuBackground",
+	theme.ColorNameOverlayBackground:   "ColorNameOverlayBackground",
+	theme.ColorNamePlaceHolder:         "ColorNamePlaceHolder",
+	theme.ColorNamePressed:             "ColorNamePressed",
+	theme.ColorNamePrimary:             "ColorNamePrimary",
+	theme.ColorNameScrollBar:           "ColorNameScrollBar",
+	theme.ColorNameSelection:           "ColorNameSelection",
+	theme.ColorNameSeparator:           "ColorNameSeparator",
+	theme.ColorNameShadow:              "ColorNameShadow",
+	theme.ColorNameSuccess:             "ColorNameSuccess",
+	theme.ColorNameWarning:             "ColorNameWarning",
+}
+
+var colorType = reflect.TypeOf((*color.Color)(nil)).Elem()
+
+func initColors() {
+	ThemeColorNames = make([]string, 0, len(themeColorConsts))
+	for name := range themeColorConsts {
+		ThemeColorNames = append(ThemeColorNames, string(name))
+	}
+	sort.Strings(ThemeColorNames)
+}
+
+// ThemeColor returns the current theme value for a color name.
+func ThemeColor(name string) color.Color {
+	return theme.Color(fyne.ThemeColorName(name))
+}
+
+// ThemeColorGoString returns the Go code that looks up a theme color by name.
+func ThemeColorGoString(name string) string {
+	if c, ok := themeColorConsts[fyne.ThemeColorName(name)]; ok {
+		return "theme.Color(theme." + c + ")"
+	}
+
+	return "theme.Color(" + strconv.Quote(name) + ")" // a custom theme may provide additional colors
+}
+
+// ThemeColorForConst returns the theme color name for the name of a constant in the theme package, such as "ColorNamePrimary".
+func ThemeColorForConst(c string) (string, bool) {
+	for name, constName := range themeColorConsts {
+		if constName == c {
+			return string(name), true
+		}
+	}
+
+	return "", false
+}
+
+// ThemeColors returns the theme color names set for the color fields of an object, keyed by the field name.
+// The names are stored in the object metadata using the field name as a key.
+func ThemeColors(obj fyne.CanvasObject, props map[string]string) map[string]string {
+	if len(props) == 0 {
+		return nil
+	}
+
+	var ret map[string]string
+	for _, field := range ColorFields(obj) {
+		if name := props[field]; name != "" {
+			if ret == nil {
+				ret = make(map[string]string)
+			}
+			ret[field] = name
+		}
+	}
+	return ret
+}
+
+// ColorFields returns the names of the fields of an object that hold a color.
+func ColorFields(obj fyne.CanvasObject) []string {
+	t := reflect.TypeOf(obj)
+	if t.Kind() != reflect.Ptr || t.Elem().Kind() != reflect.Struct {
+		return nil
+	}
+
+	var ret []string
+	for _, f := range reflect.VisibleFields(t.Elem()) {
+		if f.IsExported() && f.Type == colorType {
+			ret = append(ret, f.Name)
+		}
+	}
+	return ret
+}
@@ -19,8 +19,9 @@ func initContainers() {
 			Create: func() fyne.CanvasObject {
 				return container.NewVBox()
 			},
-			Edit: func(obj fyne.CanvasObject, props map[string]string, refresh func([]*widget.FormItem), onchanged func()) []*widget.FormItem {
+			EditWithMeta: func(obj fyne.CanvasObject, meta map[fyne.CanvasObject]map[string]string, refresh func([]*widget.FormItem), onchanged func()) []*widget.FormItem {
 				c := obj.(*fyne.Container)
+				props := meta[c]
 
 				choose := widget.NewFormItem("Layout", widget.NewSelect(layoutNames, nil))
 				items := []*widget.FormItem{choose}
@@ -28,13 +29,13 @@ func initContainers() {
 				choose.Widget.(*widget.Select).OnChanged = func(l string) {
 					lay := Layouts[l]
 					props["layout"] = l
-					c.Layout = lay.Create(c, props)
+					c.Layout = lay.Create(c, meta)
 					c.Refresh()
 
 					edit := lay.Edit
 					items = []*widget.FormItem{choose}
 					if edit != nil {
-						items = append(items, edit(c, props)...)
+						items = append(items, edit(c, meta)...)
 					}
 
 					refresh(items)
@@ -80,7 +81,7 @@ func initContainers() {
 				return children
 			},
 			AddChild: func(parent, o fyne.CanvasObject) {
-				tabs := o.(*container.AppTabs)
+				tabs := parent.(*container.AppTabs)
 
 				item := container.NewTabItem("Untitled", o)
 				tabs.Append(item)
@@ -175,7 +176,7 @@ func initContainers() {
 					}
 					str.WriteString(fmt.Sprintf("container.%s(\"%s\", ", constr, c.Text))
 					if hasIcon {
-						str.WriteString("theme." + IconName(c.Icon) + "(), ")
+						str.WriteString(ResourceGoString(c.Icon) + ", ")
 					}
 					writeGoStringExcluding(str, nil, props, defs, c.Content)
 					str.WriteString(")")
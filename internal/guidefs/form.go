@@ -0,0 +1,283 @@
+package guidefs
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+)
+
+const (
+	defaultSubmitText = "Submit"
+	defaultCancelText = "Cancel"
+)
+
+// formInfo returns the definition of the Form widget, each row of the form holds one child object.
+func formInfo() WidgetInfo {
+	return WidgetInfo{
+		Name: "Form",
+		Children: func(o fyne.CanvasObject) []fyne.CanvasObject {
+			f := o.(*widget.Form)
+
+			children := make([]fyne.CanvasObject, len(f.Items))
+			for i, item := range f.Items {
+				children[i] = item.Widget
+			}
+			return children
+		},
+		AddChild: func(parent, o fyne.CanvasObject) {
+			f := parent.(*widget.Form)
+			f.Append(fmt.Sprintf("Item %d", len(f.Items)+1), o)
+		},
+		Create: func() fyne.CanvasObject {
+			return widget.NewForm(widget.NewFormItem("Username", widget.NewEntry()), widget.NewFormItem("Password", widget.NewPasswordEntry()))
+		},
+		EditWithMeta: editForm,
+		Gostring:     formGoString,
+	}
+}
+
+func editForm(obj fyne.CanvasObject, meta map[fyne.CanvasObject]map[string]string, setItems func([]*widget.FormItem),
+	onchanged func()) []*widget.FormItem {
+	f := obj.(*widget.Form)
+	props := meta[obj]
+	if props == nil {
+		props = make(map[string]string)
+		meta[obj] = props
+	}
+	names, classes := formItemClasses()
+
+	var build func() []*widget.FormItem
+	rebuild := func() {
+		refreshForm(f)
+		setItems(build())
+		onchanged()
+	}
+	newRow := func(item *widget.FormItem, i int) *widget.FormItem {
+		text := widget.NewEntry()
+		text.SetPlaceHolder("Label")
+		text.SetText(item.Text)
+		text.OnChanged = func(s string) {
+			item.Text = s
+			f.Refresh()
+			onchanged()
+		}
+		hint := widget.NewEntry()
+		hint.SetPlaceHolder("Hint Text")
+		hint.SetText(item.HintText)
+		hint.OnChanged = func(s string) {
+			item.HintText = s
+			f.Refresh()
+			onchanged()
+		}
+		kind := widget.NewSelect(names, func(name string) {
+			forgetObject(item.Widget, meta)
+			item.Widget = Lookup(classes[name]).Create()
+			refreshForm(f)
+			onchanged()
+		})
+		if info := Lookup(formItemClass(item.Widget)); info != nil {
+			kind.Selected = info.Name
+		}
+
+		up := widget.NewButtonWithIcon("", theme.MoveUpIcon(), func() {
+			f.Items[i-1], f.Items[i] = f.Items[i], f.Items[i-1]
+			rebuild()
+		})
+		if i == 0 {
+			up.Disable()
+		}
+		down := widget.NewButtonWithIcon("", theme.MoveDownIcon(), func() {
+			f.Items[i], f.Items[i+1] = f.Items[i+1], f.Items[i]
+			rebuild()
+		})
+		if i == len(f.Items)-1 {
+			down.Disable()
+		}
+		del := widget.NewButtonWithIcon("", theme.DeleteIcon(), func() {
+			forgetObject(f.Items[i].Widget, meta)
+			f.Items = append(f.Items[:i], f.Items[i+1:]...)
+			rebuild()
+		})
+		del.Importance = widget.DangerImportance
+
+		tools := container.NewBorder(nil, nil, nil, container.NewHBox(up, down, del), container.NewVBox(text, hint, kind))
+		return widget.NewFormItem(fmt.Sprintf("Item %d", i+1), tools)
+	}
+
+	build = func() []*widget.FormItem {
+		items := make([]*widget.FormItem, 0, len(f.Items)+5)
+		for i, item := range f.Items {
+			items = append(items, newRow(item, i))
+		}
+		items = append(items, widget.NewFormItem("", widget.NewButton("Add Item", func() {
+			f.Append(fmt.Sprintf("Item %d", len(f.Items)+1), widget.NewEntry())
+			rebuild()
+		})))
+
+		submit := widget.NewEntry()
+		submit.SetText(f.SubmitText)
+		submit.OnChanged = func(s string) {
+			f.SubmitText = s
+			f.Refresh()
+			onchanged()
+		}
+		cancel := widget.NewEntry()
+		cancel.SetText(f.CancelText)
+		cancel.OnChanged = func(s string) {
+			f.CancelText = s
+			f.Refresh()
+			onchanged()
+		}
+		actionChanged := func() {
+			UpdateFormActions(f, props)
+			f.Refresh()
+			onchanged()
+		}
+		onSubmit := newActionFormItem("On Submit", "OnSubmit", props, actionChanged)
+		onSubmit.HintText = "The submit button is only shown if this is set"
+		onCancel := newActionFormItem("On Cancel", "OnCancel", props, actionChanged)
+		onCancel.HintText = "The cancel button is only shown if this is set"
+		return append(items,
+			widget.NewFormItem("Submit Text", submit),
+			widget.NewFormItem("Cancel Text", cancel),
+			onSubmit, onCancel)
+	}
+	return build()
+}
+
+func formGoString(obj fyne.CanvasObject, props map[fyne.CanvasObject]map[string]string, defs map[string]string) string {
+	f := obj.(*widget.Form)
+	str := &strings.Builder{}
+	str.WriteString("&widget.Form{Items: []*widget.FormItem{")
+	for i, item := range f.Items {
+		if i > 0 {
+			str.WriteString(",\n")
+		}
+
+		if item.HintText == "" {
+			str.WriteString(fmt.Sprintf("widget.NewFormItem(%q, ", item.Text))
+			writeGoStringExcluding(str, nil, props, defs, item.Widget)
+			str.WriteString(")")
+			continue
+		}
+
+		str.WriteString(fmt.Sprintf("&widget.FormItem{Text: %q, HintText: %q, Widget: ", item.Text, item.HintText))
+		writeGoStringExcluding(str, nil, props, defs, item.Widget)
+		str.WriteString("}")
+	}
+	str.WriteString("}")
+
+	if f.SubmitText != "" && f.SubmitText != defaultSubmitText {
+		str.WriteString(fmt.Sprintf(", SubmitText: %q", f.SubmitText))
+	}
+	if f.CancelText != "" && f.CancelText != defaultCancelText {
+		str.WriteString(fmt.Sprintf(", CancelText: %q", f.CancelText))
+	}
+	for _, action := range []string{"OnSubmit", "OnCancel"} {
+		if code := props[obj][action]; code != "" {
+			str.WriteString(fmt.Sprintf(", %s: %s", action, code))
+		}
+	}
+	str.WriteString("}")
+	return widgetRef(props[obj], defs, str.String())
+}
+
+// formItemClasses returns the names of the classes that can be placed in a form row, and a map to look up the class.
+func formItemClasses() ([]string, map[string]string) {
+	classes := make(map[string]string)
+	var names []string
+	for _, list := range [][]string{WidgetNames, CollectionNames, ContainerNames} {
+		for _, class := range list {
+			info := Lookup(class)
+			if class == IncludeClass || info == nil || info.Create == nil {
+				continue
+			}
+			if _, ok := classes[info.Name]; !ok {
+				names = append(names, info.Name)
+				classes[info.Name] = class
+			}
+		}
+	}
+
+	sort.Strings(names)
+	return names, classes
+}
+
+// formItemClass returns the class of a form row widget, including the password and multi-line variants of Entry.
+func formItemClass(o fyne.CanvasObject) string {
+	if o == nil {
+		return ""
+	}
+	if e, ok := o.(*widget.Entry); ok {
+		if e.Password {
+			return "*widget.PasswordEntry"
+		} else if e.MultiLine {
+			return "*widget.MultiLineEntry"
+		}
+	}
+
+	return reflect.TypeOf(o).String()
+}
+
+// newActionFormItem returns an item to edit the Go code of an action, such as "g.onSubmit".
+// If the code is removed the action is deleted from the properties.
+func newActionFormItem(label, action string, props map[string]string, onchanged func()) *widget.FormItem {
+	code := widget.NewEntry()
+	code.SetPlaceHolder("func() {}")
+	code.SetText(props[action])
+	code.OnChanged = func(s string) {
+		if s == "" {
+			delete(props, action)
+		} else {
+			props[action] = s
+		}
+		onchanged()
+	}
+
+	return widget.NewFormItem(label, code)
+}
+
+// UpdateFormActions shows the submit and cancel buttons of a form if their actions are set in the properties.
+// The buttons call empty functions, the action code is only run by the generated Go code.
+func UpdateFormActions(f *widget.Form, props map[string]string) {
+	f.OnSubmit, f.OnCancel = nil, nil
+	if props["OnSubmit"] != "" {
+		f.OnSubmit = func() {}
+	}
+	if props["OnCancel"] != "" {
+		f.OnCancel = func() {}
+	}
+}
+
+// forgetObject removes the metadata of an object, and the objects inside it, that was removed from a design.
+func forgetObject(obj fyne.CanvasObject, meta map[fyne.CanvasObject]map[string]string) {
+	if obj == nil {
+		return
+	}
+
+	delete(meta, obj)
+	if c, ok := obj.(*fyne.Container); ok {
+		for _, child := range c.Objects {
+			forgetObject(child, meta)
+		}
+	} else if info := Lookup(reflect.TypeOf(obj).String()); info != nil && info.IsContainer() {
+		for _, child := range info.Children(obj) {
+			forgetObject(child, meta)
+		}
+	}
+}
+
+// refreshForm rebuilds every row of a form, a refresh only creates the rows that have been appended.
+func refreshForm(f *widget.Form) {
+	items := f.Items
+	f.Items = nil
+	f.Refresh()
+	f.Items = items
+	f.Refresh()
+}
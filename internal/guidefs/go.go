@@ -15,18 +15,24 @@ func GoString(clazz string, obj fyne.CanvasObject, props map[fyne.CanvasObject]m
 		return ""
 	}
 
+	if bind := props[obj]["binding"]; bind != "" {
+		if b, ok := Bindings[clazz]; ok {
+			return widgetRef(props[obj], defs, b.Create(obj, props[obj], "g."+bind))
+		}
+	}
 	if fn := info.Gostring; fn != nil {
 		return fn(obj, props, defs)
 	}
 
 	buf := bytes.Buffer{}
-	fallbackPrint(reflect.ValueOf(obj), &buf)
+	fallbackPrint(reflect.ValueOf(obj), ThemeColors(obj, props[obj]), &buf)
 	return buf.String()
 }
 
 // fallbackPrint is derived from printValue in the BSD licensed Go source code at: src/fmt/print.go.
 // We use it here as a fallback Go printer that handles only exported fields.
-func fallbackPrint(value reflect.Value, buf *bytes.Buffer) {
+// Fields listed in themeColors are printed as a lookup of the named theme color.
+func fallbackPrint(value reflect.Value, themeColors map[string]string, buf *bytes.Buffer) {
 	switch value.Kind() {
 	case reflect.Struct:
 		t := value.Type()
@@ -53,7 +59,11 @@ func fallbackPrint(value reflect.Value, buf *bytes.Buffer) {
 
 			buf.WriteString(visible[i].Name)
 			buf.WriteByte(':')
-			fallbackPrint(f2, buf)
+			if name, ok := themeColors[visible[i].Name]; ok {
+				buf.WriteString(ThemeColorGoString(name))
+				continue
+			}
+			fallbackPrint(f2, nil, buf)
 		}
 		buf.WriteByte('}')
 	case reflect.Interface:
@@ -61,13 +71,13 @@ func fallbackPrint(value reflect.Value, buf *bytes.Buffer) {
 		if !vv.IsValid() {
 			buf.WriteString("nil")
 		} else {
-			fallbackPrint(vv, buf)
+			fallbackPrint(vv, nil, buf)
 		}
 	case reflect.Pointer:
 		switch a := value.Elem(); a.Kind() {
 		case reflect.Array, reflect.Slice, reflect.Struct, reflect.Map:
 			buf.WriteByte('&')
-			fallbackPrint(a, buf)
+			fallbackPrint(a, themeColors, buf)
 			return
 		}
 		fallthrough
@@ -12,6 +12,8 @@ import (
 	"fyne.io/fyne/v2/widget"
 )
 
+const customColorLabel = "(Custom)"
+
 var (
 	// GraphicsNames is an array with the list of names of all the graphical primitives
 	GraphicsNames []string
@@ -27,7 +29,7 @@ func initGraphics() {
 			Create: func() fyne.CanvasObject {
 				return &canvas.LinearGradient{StartColor: color.White}
 			},
-			Edit: func(obj fyne.CanvasObject, _ map[string]string, _ func([]*widget.FormItem), onchanged func()) []*widget.FormItem {
+			Edit: func(obj fyne.CanvasObject, props map[string]string, _ func([]*widget.FormItem), onchanged func()) []*widget.FormItem {
 				r := obj.(*canvas.LinearGradient)
 				angleSlide := widget.NewSlider(0, 360)
 				angleSlide.Step = 90
@@ -37,12 +39,12 @@ func initGraphics() {
 					onchanged()
 				}
 				return []*widget.FormItem{
-					widget.NewFormItem("Start", newColorButton(r.StartColor, func(c color.Color) {
+					widget.NewFormItem("Start", newColorButton(r.StartColor, props, "StartColor", func(c color.Color) {
 						r.StartColor = c
 						r.Refresh()
 						onchanged()
 					})),
-					widget.NewFormItem("End", newColorButton(r.EndColor, func(c color.Color) {
+					widget.NewFormItem("End", newColorButton(r.EndColor, props, "EndColor", func(c color.Color) {
 						r.EndColor = c
 						r.Refresh()
 						onchanged()
@@ -59,15 +61,15 @@ func initGraphics() {
 			Create: func() fyne.CanvasObject {
 				return &canvas.RadialGradient{StartColor: color.White}
 			},
-			Edit: func(obj fyne.CanvasObject, _ map[string]string, _ func([]*widget.FormItem), onchanged func()) []*widget.FormItem {
+			Edit: func(obj fyne.CanvasObject, props map[string]string, _ func([]*widget.FormItem), onchanged func()) []*widget.FormItem {
 				r := obj.(*canvas.RadialGradient)
 				return []*widget.FormItem{
-					widget.NewFormItem("Start", newColorButton(r.StartColor, func(c color.Color) {
+					widget.NewFormItem("Start", newColorButton(r.StartColor, props, "StartColor", func(c color.Color) {
 						r.StartColor = c
 						r.Refresh()
 						onchanged()
 					})),
-					widget.NewFormItem("End", newColorButton(r.EndColor, func(c color.Color) {
+					widget.NewFormItem("End", newColorButton(r.EndColor, props, "EndColor", func(c color.Color) {
 						r.EndColor = c
 						r.Refresh()
 						onchanged()
@@ -85,10 +87,10 @@ func initGraphics() {
 				rect.StrokeColor = color.Black
 				return rect
 			},
-			Edit: func(obj fyne.CanvasObject, _ map[string]string, _ func([]*widget.FormItem), onchanged func()) []*widget.FormItem {
+			Edit: func(obj fyne.CanvasObject, props map[string]string, _ func([]*widget.FormItem), onchanged func()) []*widget.FormItem {
 				r := obj.(*canvas.Rectangle)
 				return []*widget.FormItem{
-					widget.NewFormItem("Fill", newColorButton(r.FillColor, func(c color.Color) {
+					widget.NewFormItem("Fill", newColorButton(r.FillColor, props, "FillColor", func(c color.Color) {
 						r.FillColor = c
 						r.Refresh()
 						onchanged()
@@ -103,7 +105,7 @@ func initGraphics() {
 						r.Refresh()
 						onchanged()
 					})),
-					widget.NewFormItem("Color", newColorButton(r.StrokeColor, func(c color.Color) {
+					widget.NewFormItem("Color", newColorButton(r.StrokeColor, props, "StrokeColor", func(c color.Color) {
 						r.StrokeColor = c
 						r.Refresh()
 						onchanged()
@@ -121,24 +123,59 @@ func initGraphics() {
 
 // TODO tidy the API and move to a widget package
 
-func newColorButton(c color.Color, fn func(color.Color)) fyne.CanvasObject {
+// newColorButton returns an editor for a color field that accepts a color value or the name of a theme color.
+// The theme color name is stored in props using the field name as a key.
+func newColorButton(c color.Color, props map[string]string, field string, fn func(color.Color)) fyne.CanvasObject {
 	// TODO get the window passed in somehow
 	w := fyne.CurrentApp().Driver().AllWindows()[0]
 
 	input := widget.NewEntry()
 	input.SetText(formatColor(c))
+	setCustom := func(c color.Color) {
+		delete(props, field)
+		fn(c)
+	}
+	var named *widget.Select
 	preview := newColorTapper(c, func(col color.Color) {
+		named.SetSelected(customColorLabel)
 		raw := formatColor(col)
 		input.SetText(raw)
-		fn(col)
+		setCustom(col)
 	}, w)
 
 	input.OnChanged = func(raw string) {
 		c := parseColor(raw)
 		preview.setColor(c)
+		setCustom(c)
+	}
+
+	names := append([]string{customColorLabel}, ThemeColorNames...)
+	ready := false
+	named = widget.NewSelect(names, func(name string) {
+		if !ready {
+			return
+		}
+		if name == customColorLabel {
+			input.Enable()
+			setCustom(parseColor(input.Text))
+			return
+		}
+
+		input.Disable()
+		c := ThemeColor(name)
+		preview.setColor(c)
+		props[field] = name
 		fn(c)
+	})
+	if name := props[field]; name != "" {
+		named.SetSelected(name)
+		input.Disable()
+	} else {
+		named.SetSelected(customColorLabel)
 	}
-	return container.NewBorder(nil, nil, preview, nil, input)
+	ready = true
+
+	return container.NewBorder(nil, nil, preview, named, input)
 }
 
 type colorTapper struct {
@@ -0,0 +1,184 @@
+package guidefs
+
+import (
+	"path"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/widget"
+)
+
+// IncludeClass is the class of an object that includes another design file.
+const IncludeClass = "*guidefs.Include"
+
+var (
+	// LoadInclude decodes the design at a path relative to the project root, it is set by the gui package.
+	LoadInclude func(src string) (fyne.CanvasObject, map[fyne.CanvasObject]map[string]string, error)
+
+	// OpenInclude is called to open an included design for editing, if it is set.
+	OpenInclude func(fyne.URI)
+)
+
+// Include is a placeholder for a design that is loaded from another .gui.json file.
+// The design is shown read-only, it is edited by opening the file that it was loaded from.
+type Include struct {
+	widget.BaseWidget
+
+	// Src is the path of the included design, relative to the project root.
+	Src string
+
+	content fyne.CanvasObject
+	meta    map[fyne.CanvasObject]map[string]string
+}
+
+// NewInclude returns an object that shows the content loaded from the design file at src,
+// the metadata of the included design is kept separate from the design that includes it.
+func NewInclude(src string, content fyne.CanvasObject, meta map[fyne.CanvasObject]map[string]string) *Include {
+	i := &Include{Src: src, content: content, meta: meta}
+	i.ExtendBaseWidget(i)
+	return i
+}
+
+// Content returns the objects loaded from the included design and their metadata, or nil if it is not loaded.
+func (i *Include) Content() (fyne.CanvasObject, map[fyne.CanvasObject]map[string]string) {
+	return i.content, i.meta
+}
+
+// Load replaces the content with the design at src.
+// If the design cannot be loaded the error is returned and a message is shown in its place.
+func (i *Include) Load(src string) error {
+	i.Src = src
+	i.content, i.meta = nil, nil
+
+	var err error
+	if src != "" && LoadInclude != nil {
+		i.content, i.meta, err = LoadInclude(src)
+	}
+	i.Refresh()
+	return err
+}
+
+func (i *Include) CreateRenderer() fyne.WidgetRenderer {
+	r := &includeRenderer{inc: i, stack: container.NewStack(), missing: widget.NewLabel("")}
+	r.Refresh()
+	return r
+}
+
+// IncludeComponent returns the name of the component that is generated for a design file,
+// for example "header" for "ui/header.gui.json".
+// It is empty if no design is included.
+func IncludeComponent(src string) string {
+	if src == "" {
+		return ""
+	}
+	return strings.TrimSuffix(path.Base(src), ".gui.json")
+}
+
+// IncludeGoNames returns the names of the type and constructor function that are generated for a design file,
+// for example "headerGui" and "newHeaderGUI()" for "header.gui.json". They are empty if no design is included.
+func IncludeGoNames(src string) (typeName, constructor string) {
+	component := IncludeComponent(src)
+	switch component {
+	case "":
+		return "", ""
+	case "main": // the main design has no prefix
+		return "gui", "newGUI()"
+	}
+
+	return component + "Gui", "new" + strings.ToUpper(component[0:1]) + component[1:] + "GUI()"
+}
+
+type includeRenderer struct {
+	inc     *Include
+	stack   *fyne.Container
+	missing *widget.Label
+}
+
+func (r *includeRenderer) Destroy() {
+}
+
+func (r *includeRenderer) Layout(s fyne.Size) {
+	r.stack.Resize(s)
+}
+
+func (r *includeRenderer) MinSize() fyne.Size {
+	return r.stack.MinSize()
+}
+
+func (r *includeRenderer) Objects() []fyne.CanvasObject {
+	return []fyne.CanvasObject{r.stack}
+}
+
+func (r *includeRenderer) Refresh() {
+	if r.inc.content != nil {
+		r.stack.Objects = []fyne.CanvasObject{r.inc.content}
+	} else {
+		if r.inc.Src == "" {
+			r.missing.SetText("Choose a design to include")
+		} else {
+			r.missing.SetText("Missing design " + r.inc.Src)
+		}
+		r.stack.Objects = []fyne.CanvasObject{r.missing}
+	}
+	r.stack.Refresh()
+}
+
+func includeInfo() WidgetInfo {
+	return WidgetInfo{
+		Name: "Include",
+		Create: func() fyne.CanvasObject {
+			return NewInclude("", nil, nil)
+		},
+		Edit: func(obj fyne.CanvasObject, _ map[string]string, _ func([]*widget.FormItem), onchanged func()) []*widget.FormItem {
+			inc := obj.(*Include)
+			open := widget.NewButton("Open for Editing", func() {
+				u, err := projectURI(inc.Src)
+				if err != nil {
+					fyne.LogError("Failed to find included design", err)
+					return
+				}
+				OpenInclude(u)
+			})
+			if inc.Src == "" || OpenInclude == nil {
+				open.Disable()
+			}
+
+			src := widget.NewSelect(ProjectDesignNames(), nil)
+			src.Selected = inc.Src
+			src.OnChanged = func(s string) {
+				if err := inc.Load(s); err != nil {
+					fyne.LogError("Failed to load included design", err)
+				}
+				if OpenInclude != nil {
+					open.Enable()
+				}
+				onchanged()
+			}
+			return []*widget.FormItem{
+				widget.NewFormItem("Design", src),
+				widget.NewFormItem("", open),
+			}
+		},
+		Gostring: func(obj fyne.CanvasObject, props map[fyne.CanvasObject]map[string]string, defs map[string]string) string {
+			_, create := IncludeGoNames(obj.(*Include).Src)
+			if create == "" {
+				return "container.NewStack()"
+			}
+
+			name := props[obj]["name"]
+			if name == "" {
+				return create + ".makeUI()"
+			}
+
+			defs[name] = create
+			return "g." + name + ".makeUI()"
+		},
+		Packages: func(obj fyne.CanvasObject) []string {
+			if IncludeComponent(obj.(*Include).Src) == "" {
+				return []string{"container"}
+			}
+			return []string{}
+		},
+	}
+}
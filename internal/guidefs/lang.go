@@ -0,0 +1,109 @@
+package guidefs
+
+import (
+	"reflect"
+	"strconv"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/widget"
+)
+
+// translationPrefix is added to the name of a text field to store its translation key in the object metadata.
+const translationPrefix = "lang."
+
+// TranslatableFields lists the text fields of each widget type that can be translated.
+var TranslatableFields = map[string][]string{
+	"*widget.Button":    {"Text"},
+	"*widget.Card":      {"Title", "Subtitle"},
+	"*widget.Check":     {"Text"},
+	"*widget.Entry":     {"PlaceHolder"},
+	"*widget.Hyperlink": {"Text"},
+	"*widget.Label":     {"Text"},
+}
+
+// TranslationKey returns the key used to translate a text field, or "" if the field is not translated.
+func TranslationKey(props map[string]string, field string) string {
+	return props[translationPrefix+field]
+}
+
+// SetTranslationKey marks a text field as translated using the key, an empty key removes the translation.
+func SetTranslationKey(props map[string]string, field, key string) {
+	if key == "" {
+		delete(props, translationPrefix+field)
+		return
+	}
+
+	props[translationPrefix+field] = key
+}
+
+// Translations returns the translation keys of the text fields of an object, keyed by the field name.
+func Translations(clazz string, props map[string]string) map[string]string {
+	if len(props) == 0 {
+		return nil
+	}
+
+	var ret map[string]string
+	for _, field := range TranslatableFields[clazz] {
+		if key := TranslationKey(props, field); key != "" {
+			if ret == nil {
+				ret = make(map[string]string)
+			}
+			ret[field] = key
+		}
+	}
+	return ret
+}
+
+// TextGoString returns the Go code for the text of a field, which is looked up with the lang package if it is translated.
+// Text that uses its source string as the key calls `lang.L`, otherwise the source string is the fallback of `lang.X`.
+func TextGoString(props map[string]string, field, text string) string {
+	key := TranslationKey(props, field)
+	switch key {
+	case "":
+		return "\"" + escapeLabel(text) + "\""
+	case text:
+		return "lang.L(" + strconv.Quote(key) + ")"
+	}
+
+	return "lang.X(" + strconv.Quote(key) + ", " + strconv.Quote(text) + ")"
+}
+
+// NewTranslationFormItems returns form items for marking the text fields of a widget as translated.
+// The current text of a field is used as its key when translation is enabled.
+func NewTranslationFormItems(obj fyne.CanvasObject, props map[string]string, onchanged func()) []*widget.FormItem {
+	var items []*widget.FormItem
+	for _, field := range TranslatableFields[reflect.TypeOf(obj).String()] {
+		field := field
+		key := widget.NewEntry()
+		key.SetPlaceHolder("(Not translated)")
+		key.SetText(TranslationKey(props, field))
+
+		translate := widget.NewCheck("", nil)
+		translate.Checked = key.Text != ""
+		if !translate.Checked {
+			key.Disable()
+		}
+		translate.OnChanged = func(on bool) {
+			if !on {
+				key.SetText("")
+				key.Disable()
+				return
+			}
+
+			key.Enable()
+			if key.Text == "" {
+				key.SetText(reflect.ValueOf(obj).Elem().FieldByName(field).String())
+			}
+		}
+		key.OnChanged = func(s string) {
+			SetTranslationKey(props, field, s)
+			onchanged()
+		}
+
+		item := widget.NewFormItem("Translate "+field, container.NewBorder(nil, nil, translate, nil, key))
+		item.HintText = "Key for lang.L"
+		items = append(items, item)
+	}
+	return items
+}
@@ -4,6 +4,7 @@ import (
 	"errors"
 	"fmt"
 	"reflect"
+	"regexp"
 	"sort"
 	"strconv"
 	"strings"
@@ -13,9 +14,11 @@ import (
 	"fyne.io/fyne/v2/widget"
 )
 
+// layoutInfo creates, edits and generates the code of a layout. The functions are passed the metadata of the design,
+// which must include the properties of the container, so that a layout can refer to the children of the container.
 type layoutInfo struct {
-	Create func(*fyne.Container, map[string]string) fyne.Layout
-	Edit   func(*fyne.Container, map[string]string) []*widget.FormItem
+	Create func(*fyne.Container, map[fyne.CanvasObject]map[string]string) fyne.Layout
+	Edit   func(*fyne.Container, map[fyne.CanvasObject]map[string]string) []*widget.FormItem
 	goText func(*fyne.Container, map[fyne.CanvasObject]map[string]string, map[string]string) string
 }
 
@@ -23,152 +26,89 @@ var (
 	// layoutNames is an array with the list of names of all the Layouts
 	layoutNames = extractLayoutNames()
 
+	// LayoutProperties maps layout names to the container properties they read, with a pattern for the valid values.
+	// An empty value means that the layout uses its default.
+	LayoutProperties = map[string]map[string]string{
+		"Border": {
+			"top":    NodeIDPattern,
+			"bottom": NodeIDPattern,
+			"left":   NodeIDPattern,
+			"right":  NodeIDPattern,
+		},
+		"Grid": {
+			"grid_type": `^(Columns|Rows)?$`,
+			"count":     `^[0-9]*$`,
+		},
+		"GridWrap": {
+			"width":  `^[0-9]*$`,
+			"height": `^[0-9]*$`,
+		},
+		"HBox": {"dir": `^horizontal$`},
+		"VBox": {"dir": `^vertical$`},
+	}
+
 	// Layouts maps container names to layout information to create and edit containers, and generate code
 	Layouts = map[string]layoutInfo{
 		"Border": {
-			func(c *fyne.Container, props map[string]string) fyne.Layout {
-				topNum := props["top"]
-				topID, _ := strconv.Atoi(topNum)
-				bottomNum := props["bottom"]
-				bottomID, _ := strconv.Atoi(bottomNum)
-				leftNum := props["left"]
-				leftID, _ := strconv.Atoi(leftNum)
-				rightNum := props["right"]
-				rightID, _ := strconv.Atoi(rightNum)
-
-				var t, b, l, r fyne.CanvasObject
-				if topNum != "" && topID < len(c.Objects) {
-					t = c.Objects[topID]
-				}
-				if bottomNum != "" && bottomID < len(c.Objects) {
-					b = c.Objects[bottomID]
-				}
-				if leftNum != "" && leftID < len(c.Objects) {
-					l = c.Objects[leftID]
-				}
-				if rightNum != "" && rightID < len(c.Objects) {
-					r = c.Objects[rightID]
-				}
-
-				return layout.NewBorderLayout(t, b, l, r)
+			func(c *fyne.Container, meta map[fyne.CanvasObject]map[string]string) fyne.Layout {
+				return layout.NewBorderLayout(borderObjects(c, meta))
 			},
-			func(c *fyne.Container, props map[string]string) []*widget.FormItem {
-				topNum := props["top"]
-				topID, _ := strconv.Atoi(topNum)
-				bottomNum := props["bottom"]
-				bottomID, _ := strconv.Atoi(bottomNum)
-				leftNum := props["left"]
-				leftID, _ := strconv.Atoi(leftNum)
-				rightNum := props["right"]
-				rightID, _ := strconv.Atoi(rightNum)
-
-				var t, b, l, r fyne.CanvasObject
+			func(c *fyne.Container, meta map[fyne.CanvasObject]map[string]string) []*widget.FormItem {
+				props := meta[c]
 				list := []string{"(Empty)"}
 				for _, w := range c.Objects {
-					label := ""
-					if c, ok := w.(*fyne.Container); ok {
-						name := props["name"]
-						if name == "" {
-							name = fmt.Sprintf("%p", c)
-						}
-						label = fmt.Sprintf("Container (%s)", name)
-					} else {
-						wid := w.(fyne.Widget)
-						name := props["name"]
+					name := meta[w]["name"]
+					if _, ok := w.(*fyne.Container); ok {
 						if name == "" {
-							name = widgetName(w)
+							name = fmt.Sprintf("%p", w)
 						}
-						label = fmt.Sprintf("%s (%s)", reflect.TypeOf(wid).Elem().Name(), name)
+						list = append(list, fmt.Sprintf("Container (%s)", name))
+						continue
 					}
-					list = append(list, label)
-				}
-				top := widget.NewSelect(list, nil)
-				if topNum != "" && topID < len(c.Objects) {
-					top.SetSelectedIndex(topID + 1)
-					t = c.Objects[topID]
-				}
-				bottom := widget.NewSelect(list, nil)
-				if bottomNum != "" && bottomID < len(c.Objects) {
-					bottom.SetSelectedIndex(bottomID + 1)
-					b = c.Objects[bottomID]
-				}
-				left := widget.NewSelect(list, nil)
-				if leftNum != "" && leftID < len(c.Objects) {
-					left.SetSelectedIndex(leftID + 1)
-					l = c.Objects[leftID]
-				}
-				right := widget.NewSelect(list, nil)
-				if rightNum != "" && rightID < len(c.Objects) {
-					right.SetSelectedIndex(rightID + 1)
-					r = c.Objects[rightID]
+
+					if name == "" {
+						name = widgetName(w)
+					}
+					list = append(list, fmt.Sprintf("%s (%s)", reflect.TypeOf(w).Elem().Name(), name))
 				}
+
+				edges := make([]*widget.Select, len(borderEdges))
 				change := func(string) {
-					t, b, l, r = nil, nil, nil, nil
-					props["top"] = ""
-					props["bottom"] = ""
-					props["left"] = ""
-					props["right"] = ""
-					if top.SelectedIndex() > 0 {
-						props["top"] = strconv.Itoa(top.SelectedIndex() - 1)
-						t = c.Objects[top.SelectedIndex()-1]
-					}
-					if bottom.SelectedIndex() > 0 {
-						props["bottom"] = strconv.Itoa(bottom.SelectedIndex() - 1)
-						b = c.Objects[bottom.SelectedIndex()-1]
-					}
-					if left.SelectedIndex() > 0 {
-						props["left"] = strconv.Itoa(left.SelectedIndex() - 1)
-						l = c.Objects[left.SelectedIndex()-1]
-					}
-					if right.SelectedIndex() > 0 {
-						props["right"] = strconv.Itoa(right.SelectedIndex() - 1)
-						r = c.Objects[right.SelectedIndex()-1]
+					for i, edge := range borderEdges {
+						props[edge] = ""
+						if index := edges[i].SelectedIndex(); index > 0 {
+							props[edge] = NodeID(c.Objects[index-1], meta)
+						}
 					}
 
-					c.Layout = layout.NewBorderLayout(t, b, l, r)
+					c.Layout = layout.NewBorderLayout(borderObjects(c, meta))
 					c.Refresh()
 				}
-				top.OnChanged = change
-				bottom.OnChanged = change
-				left.OnChanged = change
-				right.OnChanged = change
-				c.Layout = layout.NewBorderLayout(t, b, l, r)
 
-				return []*widget.FormItem{
-					widget.NewFormItem("Top", top),
-					widget.NewFormItem("Bottom", bottom),
-					widget.NewFormItem("Left", left),
-					widget.NewFormItem("Right", right),
-					widget.NewFormItem("Middle", widget.NewLabel("(all other widgets)")),
+				items := make([]*widget.FormItem, 0, len(borderEdges)+1)
+				for i, edge := range borderEdges {
+					edges[i] = widget.NewSelect(list, nil)
+					if child := ChildByID(c, meta, props[edge]); child != nil {
+						for index, o := range c.Objects {
+							if o == child {
+								edges[i].SetSelectedIndex(index + 1)
+							}
+						}
+					}
+					edges[i].OnChanged = change
+					items = append(items, widget.NewFormItem(borderEdgeLabels[i], edges[i]))
 				}
+				c.Layout = layout.NewBorderLayout(borderObjects(c, meta))
+
+				return append(items, widget.NewFormItem("Middle", widget.NewLabel("(all other widgets)")))
 			},
 			func(c *fyne.Container, props map[fyne.CanvasObject]map[string]string, defs map[string]string) string {
-				topNum := props[c]["top"]
-				topID, _ := strconv.Atoi(topNum)
-				bottomNum := props[c]["bottom"]
-				bottomID, _ := strconv.Atoi(bottomNum)
-				leftNum := props[c]["left"]
-				leftID, _ := strconv.Atoi(leftNum)
-				rightNum := props[c]["right"]
-				rightID, _ := strconv.Atoi(rightNum)
-
+				t, b, l, r := borderObjects(c, props)
 				ignored := 0
-				var t, b, l, r fyne.CanvasObject
-				if topNum != "" && topID < len(c.Objects) {
-					t = c.Objects[topID]
-					ignored++
-				}
-				if bottomNum != "" && bottomID < len(c.Objects) {
-					b = c.Objects[bottomID]
-					ignored++
-				}
-				if leftNum != "" && leftID < len(c.Objects) {
-					l = c.Objects[leftID]
-					ignored++
-				}
-				if rightNum != "" && rightID < len(c.Objects) {
-					r = c.Objects[rightID]
-					ignored++
+				for _, o := range []fyne.CanvasObject{t, b, l, r} {
+					if o != nil {
+						ignored++
+					}
 				}
 
 				str := &strings.Builder{}
@@ -191,21 +131,22 @@ var (
 			},
 		},
 		"Center": {
-			func(*fyne.Container, map[string]string) fyne.Layout {
+			func(*fyne.Container, map[fyne.CanvasObject]map[string]string) fyne.Layout {
 				return layout.NewCenterLayout()
 			},
 			nil,
 			nil,
 		},
 		"Form": {
-			func(*fyne.Container, map[string]string) fyne.Layout {
+			func(*fyne.Container, map[fyne.CanvasObject]map[string]string) fyne.Layout {
 				return layout.NewFormLayout()
 			},
 			nil,
 			nil,
 		},
 		"Grid": {
-			func(c *fyne.Container, props map[string]string) fyne.Layout {
+			func(c *fyne.Container, meta map[fyne.CanvasObject]map[string]string) fyne.Layout {
+				props := meta[c]
 				rowCol := props["grid_type"]
 				if rowCol == "" {
 					rowCol = "Columns"
@@ -225,7 +166,8 @@ var (
 				}
 				return layout.NewGridLayoutWithColumns(int(num))
 			},
-			func(c *fyne.Container, props map[string]string) []*widget.FormItem {
+			func(c *fyne.Container, meta map[fyne.CanvasObject]map[string]string) []*widget.FormItem {
+				props := meta[c]
 				rowCol := props["grid_type"]
 				if rowCol == "" {
 					rowCol = "Columns"
@@ -291,7 +233,8 @@ var (
 			},
 		},
 		"GridWrap": {
-			func(c *fyne.Container, props map[string]string) fyne.Layout {
+			func(c *fyne.Container, meta map[fyne.CanvasObject]map[string]string) fyne.Layout {
+				props := meta[c]
 				width := props["width"]
 				if width == "" {
 					width = "100"
@@ -311,7 +254,8 @@ var (
 
 				return layout.NewGridWrapLayout(fyne.NewSize(float32(w), float32(h)))
 			},
-			func(c *fyne.Container, props map[string]string) []*widget.FormItem {
+			func(c *fyne.Container, meta map[fyne.CanvasObject]map[string]string) []*widget.FormItem {
+				props := meta[c]
 				width := props["width"]
 				if width == "" {
 					width = "100"
@@ -356,37 +300,37 @@ var (
 			nil,
 		},
 		"HBox": {
-			func(c *fyne.Container, props map[string]string) fyne.Layout {
-				props["dir"] = "horizontal"
+			func(c *fyne.Container, meta map[fyne.CanvasObject]map[string]string) fyne.Layout {
+				meta[c]["dir"] = "horizontal"
 				return layout.NewHBoxLayout()
 			},
 			nil,
 			nil,
 		},
 		"Max": {
-			func(c *fyne.Container, props map[string]string) fyne.Layout {
+			func(*fyne.Container, map[fyne.CanvasObject]map[string]string) fyne.Layout {
 				return layout.NewStackLayout()
 			},
 			nil,
 			nil,
 		},
 		"Padded": {
-			func(c *fyne.Container, props map[string]string) fyne.Layout {
+			func(*fyne.Container, map[fyne.CanvasObject]map[string]string) fyne.Layout {
 				return layout.NewPaddedLayout()
 			},
 			nil,
 			nil,
 		},
 		"Stack": {
-			func(c *fyne.Container, props map[string]string) fyne.Layout {
+			func(*fyne.Container, map[fyne.CanvasObject]map[string]string) fyne.Layout {
 				return layout.NewStackLayout()
 			},
 			nil,
 			nil,
 		},
 		"VBox": {
-			func(c *fyne.Container, props map[string]string) fyne.Layout {
-				props["dir"] = "vertical"
+			func(c *fyne.Container, meta map[fyne.CanvasObject]map[string]string) fyne.Layout {
+				meta[c]["dir"] = "vertical"
 				return layout.NewVBoxLayout()
 			},
 			nil,
@@ -395,6 +339,151 @@ var (
 	}
 )
 
+// CustomLayout describes a layout that is added to the builder by `RegisterLayout`.
+type CustomLayout struct {
+	Create     func(*fyne.Container, map[fyne.CanvasObject]map[string]string) fyne.Layout
+	Edit       func(*fyne.Container, map[fyne.CanvasObject]map[string]string) []*widget.FormItem
+	Properties map[string]string
+	Gostring   func(*fyne.Container, map[fyne.CanvasObject]map[string]string) string
+	Packages   func(*fyne.Container) []string
+}
+
+// CustomLayouts maps the names of the layouts added by `RegisterLayout` to their definitions.
+var CustomLayouts = map[string]CustomLayout{}
+
+// RegisterLayout adds a layout to the list that containers can pick from, replacing any layout with the same name.
+// The Go code of a container with the layout passes the code returned by Gostring to `container.New`, with the children.
+// An error is returned, and nothing registered, if the name is empty, Create or Gostring is missing,
+// or a property pattern is not a valid regular expression.
+func RegisterLayout(name string, info CustomLayout) error {
+	InitOnce()
+
+	switch {
+	case name == "":
+		return errors.New("layout name must not be empty")
+	case info.Create == nil:
+		return fmt.Errorf("layout %s must have a Create function", name)
+	case info.Gostring == nil:
+		return fmt.Errorf("layout %s must have a Gostring function", name)
+	}
+	for prop, pattern := range info.Properties {
+		if _, err := regexp.Compile(pattern); err != nil {
+			return fmt.Errorf("layout %s has an invalid pattern for property %s: %w", name, prop, err)
+		}
+	}
+
+	CustomLayouts[name] = info
+	if len(info.Properties) > 0 {
+		LayoutProperties[name] = info.Properties
+	} else {
+		delete(LayoutProperties, name)
+	}
+
+	Layouts[name] = layoutInfo{info.Create, info.Edit,
+		func(c *fyne.Container, props map[fyne.CanvasObject]map[string]string, defs map[string]string) string {
+			str := &strings.Builder{}
+			str.WriteString("container.New(" + info.Gostring(c, props))
+			if len(c.Objects) > 0 {
+				str.WriteString(", ")
+				writeGoStringExcluding(str, nil, props, defs, c.Objects...)
+			}
+			str.WriteString(")")
+			return widgetRef(props[c], defs, str.String())
+		}}
+	layoutNames = extractLayoutNames()
+	return nil
+}
+
+// UnregisterLayout removes a layout that was added by `RegisterLayout`.
+func UnregisterLayout(name string) {
+	if _, ok := CustomLayouts[name]; !ok {
+		return
+	}
+
+	delete(CustomLayouts, name)
+	delete(LayoutProperties, name)
+	delete(Layouts, name)
+	layoutNames = extractLayoutNames()
+}
+
+// borderEdges are the properties of a border container that refer to the children shown at each edge.
+var (
+	borderEdges      = []string{"top", "bottom", "left", "right"}
+	borderEdgeLabels = []string{"Top", "Bottom", "Left", "Right"}
+)
+
+// NodeIDPattern matches the node IDs that properties use to refer to an object, or an empty reference.
+const NodeIDPattern = `^([a-zA-Z_][a-zA-Z0-9_]*)?$`
+
+// NodeID returns the ID that the properties of a container use to refer to one of its children,
+// such as the edges of a border. If the object has no ID a new one that is unique within the design is stored in its metadata,
+// so that the reference stays valid when children are added, removed or reordered.
+func NodeID(obj fyne.CanvasObject, meta map[fyne.CanvasObject]map[string]string) string {
+	if id := meta[obj]["id"]; id != "" {
+		return id
+	}
+
+	used := make(map[string]bool, len(meta))
+	for _, props := range meta {
+		used[props["id"]] = true
+	}
+	id := UnusedNodeID(used)
+
+	if meta[obj] == nil {
+		meta[obj] = make(map[string]string)
+	}
+	meta[obj]["id"] = id
+	return id
+}
+
+// UnusedNodeID returns the first node ID, such as "node1", that is not in the set of used IDs.
+func UnusedNodeID(used map[string]bool) string {
+	for i := 1; ; i++ {
+		if id := "node" + strconv.Itoa(i); !used[id] {
+			return id
+		}
+	}
+}
+
+// ChildByID returns the child of a container that has the node ID, or nil if there is no such child.
+func ChildByID(c *fyne.Container, meta map[fyne.CanvasObject]map[string]string, id string) fyne.CanvasObject {
+	if id == "" {
+		return nil
+	}
+
+	for _, o := range c.Objects {
+		if meta[o]["id"] == id {
+			return o
+		}
+	}
+	return nil
+}
+
+// UpdateLayout creates the layout of a container again after its children changed.
+// References to children that were removed are cleared from the properties.
+func UpdateLayout(c *fyne.Container, meta map[fyne.CanvasObject]map[string]string) {
+	props := meta[c]
+	if props["layout"] == "Border" {
+		for _, edge := range borderEdges {
+			if props[edge] != "" && ChildByID(c, meta, props[edge]) == nil {
+				props[edge] = ""
+			}
+		}
+	}
+
+	if lay, ok := Layouts[props["layout"]]; ok {
+		c.Layout = lay.Create(c, meta)
+	}
+	c.Refresh()
+}
+
+// borderObjects returns the children of a border container that are shown at the top, bottom, left and right edges.
+func borderObjects(c *fyne.Container, meta map[fyne.CanvasObject]map[string]string) (t, b, l, r fyne.CanvasObject) {
+	props := meta[c]
+	return ChildByID(c, meta, props["top"]), ChildByID(c, meta, props["bottom"]),
+		ChildByID(c, meta, props["left"]), ChildByID(c, meta, props["right"])
+}
+
 // extractLayoutNames returns all the list of names of all the Layouts known
 func extractLayoutNames() []string {
 	var layoutsNamesFromData = make([]string, len(Layouts))
@@ -1,17 +1,37 @@
 package guidefs
 
 import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
 	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/storage"
 )
 
+// ProjectRoot is the directory that project resource paths are relative to.
+// If it is nil then paths are relative to the current working directory.
+var ProjectRoot fyne.URI
+
+var projectResourceExtensions = []string{".gif", ".jpeg", ".jpg", ".png", ".svg"}
+
 type jsonResource struct {
 	fyne.Resource `json:"-"`
 }
 
 func (r *jsonResource) MarshalJSON() ([]byte, error) {
-	icon := "\"" + IconName(r.Resource) + "\""
+	return json.Marshal(IconName(r.Resource))
+}
 
-	return []byte(icon), nil
+// projectResource is an image file in the project, referenced by its path relative to the project root
+type projectResource struct {
+	fyne.Resource
+	path string
 }
 
 // WrapResource wraps a fyne.Resource for integration with JSON
@@ -19,8 +39,11 @@ func WrapResource(r fyne.Resource) fyne.Resource {
 	return &jsonResource{r}
 }
 
-// IconName returns the name for an icon
+// IconName returns the name for an icon, or the path of a project resource
 func IconName(res fyne.Resource) string {
+	if p, ok := res.(*projectResource); ok {
+		return p.path
+	}
 	name := res.Name()
 	// strip prefix numbers to unwrap
 	for name[0] >= '0' && name[0] <= '9' {
@@ -34,3 +57,108 @@ func IconName(res fyne.Resource) string {
 
 	return ret
 }
+
+// IsProjectResource returns true if the icon name refers to an image file in the project instead of a theme icon.
+func IsProjectResource(name string) bool {
+	return isProjectResourceFile(name)
+}
+
+// LoadProjectResource loads the image file at the path, which is relative to the `ProjectRoot`.
+func LoadProjectResource(name string) (fyne.Resource, error) {
+	u, err := projectURI(name)
+	if err != nil {
+		return nil, err
+	}
+	res, err := storage.LoadResourceFromURI(u)
+	if err != nil {
+		return nil, err
+	}
+
+	return &projectResource{Resource: res, path: name}, nil
+}
+
+// ProjectResourceNames returns the paths of the image files in the project, sorted by name.
+func ProjectResourceNames() []string {
+	return projectFileNames(isProjectResourceFile)
+}
+
+func isProjectResourceFile(name string) bool {
+	ext := strings.ToLower(path.Ext(name))
+	for _, e := range projectResourceExtensions {
+		if ext == e {
+			return true
+		}
+	}
+	return false
+}
+
+// ProjectDesignNames returns the paths of the .gui.json design files in the project, sorted by name.
+func ProjectDesignNames() []string {
+	return projectFileNames(func(name string) bool {
+		return strings.HasSuffix(name, ".gui.json")
+	})
+}
+
+// projectFileNames returns the paths of the files in the project that match, skipping hidden directories.
+func projectFileNames(match func(name string) bool) []string {
+	root := "."
+	if ProjectRoot != nil {
+		root = ProjectRoot.Path()
+	}
+
+	var names []string
+	_ = filepath.WalkDir(root, func(p string, d os.DirEntry, err error) error {
+		if err != nil {
+			return nil // skip unreadable items
+		}
+		if d.IsDir() {
+			if p != root && strings.HasPrefix(d.Name(), ".") {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		if match(d.Name()) {
+			rel, err := filepath.Rel(root, p)
+			if err == nil {
+				names = append(names, filepath.ToSlash(rel))
+			}
+		}
+		return nil
+	})
+
+	sort.Strings(names)
+	return names
+}
+
+// ResourceGoString returns the Go code for a resource, either a theme icon or a project resource loaded when the app runs.
+// Project resources use the `loadResource` helper generated for the GUI type.
+func ResourceGoString(res fyne.Resource) string {
+	if p, ok := res.(*projectResource); ok {
+		return "g.loadResource(" + strconv.Quote(p.path) + ")"
+	}
+
+	return "theme." + IconName(res) + "()"
+}
+
+func projectURI(name string) (fyne.URI, error) {
+	if name == "" || path.IsAbs(filepath.ToSlash(name)) || filepath.IsAbs(name) || filepath.VolumeName(name) != "" {
+		return nil, fmt.Errorf("path %q must be relative to the project", name)
+	}
+	for _, elem := range strings.Split(filepath.ToSlash(name), "/") {
+		if elem == ".." {
+			return nil, fmt.Errorf("path %q must not leave the project", name)
+		}
+	}
+
+	name = path.Clean(filepath.ToSlash(name))
+	if ProjectRoot != nil {
+		return storage.ParseURI(ProjectRoot.String() + "/" + name)
+	}
+
+	abs, err := filepath.Abs(filepath.FromSlash(name))
+	if err != nil {
+		return nil, err
+	}
+	return storage.NewFileURI(abs), nil
+}
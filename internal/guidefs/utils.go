@@ -41,13 +41,48 @@ func newIconSelectorButton(ic fyne.Resource, fn func(fyne.Resource), showName bo
 			},
 		}
 	}
+	if files := ProjectResourceNames(); len(files) > 0 {
+		project := make([]*fyne.MenuItem, 0, len(files))
+		for _, n := range files {
+			name := n
+			res, err := LoadProjectResource(name)
+			if err != nil {
+				fyne.LogError("Failed to load project resource "+name, err)
+				continue
+			}
+
+			project = append(project, &fyne.MenuItem{
+				Label: name,
+				Icon:  res,
+				Action: func() {
+					if showName {
+						iconSel.SetText(name)
+					} else {
+						iconSel.SetText("")
+					}
+					iconSel.SetIcon(res)
+					fn(res)
+				},
+			})
+		}
+
+		items = append(items, fyne.NewMenuItemSeparator(),
+			&fyne.MenuItem{Label: "Project", ChildMenu: fyne.NewMenu("", project...)})
+	}
 	iconSel = widget.NewButton(noIconLabel, func() {
 		d := fyne.CurrentApp().Driver()
 		c := d.CanvasForObject(iconSel)
 		p := d.AbsolutePositionForObject(iconSel).AddXY(0, iconSel.Size().Height)
 		widget.NewPopUpMenu(fyne.NewMenu("", items...), c).ShowAtPosition(p)
 	})
-	if ic != nil {
+	if p, ok := ic.(*projectResource); ok {
+		if showName {
+			iconSel.SetText(p.path)
+		} else {
+			iconSel.SetText("")
+		}
+		iconSel.SetIcon(p)
+	} else if ic != nil {
 		name := IconName(ic)
 		for _, n := range IconNames {
 			if n == name {
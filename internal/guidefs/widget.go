@@ -3,6 +3,7 @@
 package guidefs
 
 import (
+	"errors"
 	"fmt"
 	"net/url"
 	"sort"
@@ -45,8 +46,11 @@ type WidgetInfo struct {
 	AddChild func(parent, child fyne.CanvasObject)
 	Create   func() fyne.CanvasObject
 	Edit     func(fyne.CanvasObject, map[string]string, func([]*widget.FormItem), func()) []*widget.FormItem
-	Gostring func(fyne.CanvasObject, map[fyne.CanvasObject]map[string]string, map[string]string) string
-	Packages func(object fyne.CanvasObject) []string
+	// EditWithMeta is used instead of Edit for objects whose properties refer to other objects of the design,
+	// it is passed the metadata of the whole design rather than the properties of the object.
+	EditWithMeta func(fyne.CanvasObject, map[fyne.CanvasObject]map[string]string, func([]*widget.FormItem), func()) []*widget.FormItem
+	Gostring     func(fyne.CanvasObject, map[fyne.CanvasObject]map[string]string, map[string]string) string
+	Packages     func(object fyne.CanvasObject) []string
 }
 
 // IsContainer indicates wether a widget children or not
@@ -129,26 +133,24 @@ func initWidgets() {
 			},
 			Gostring: func(obj fyne.CanvasObject, props map[fyne.CanvasObject]map[string]string, defs map[string]string) string {
 				b := obj.(*widget.Button)
-				action := props[obj]["OnTapped"]
-				if action == "" {
-					action = "func() {}"
-				}
+				text := TextGoString(props[obj], "Text", b.Text)
+				action := actionCode(props[obj], "OnTapped")
 				if b.Icon == nil {
 					if b.Importance == widget.MediumImportance && b.Alignment == widget.ButtonAlignCenter {
-						return widgetRef(props[obj], defs, fmt.Sprintf("widget.NewButton(\"%s\", %s)", escapeLabel(b.Text), action))
+						return widgetRef(props[obj], defs, fmt.Sprintf("widget.NewButton(%s, %s)", text, action))
 					}
 
-					return widgetRef(props[obj], defs, fmt.Sprintf("&widget.Button{Text: \"%s\", Importance: %d, Alignment: %d, OnTapped: %s}",
-						escapeLabel(b.Text), b.Importance, b.Alignment, action))
+					return widgetRef(props[obj], defs, fmt.Sprintf("&widget.Button{Text: %s, Importance: %d, Alignment: %d, OnTapped: %s}",
+						text, b.Importance, b.Alignment, action))
 				}
 
-				icon := "theme." + IconName(b.Icon) + "()"
+				icon := ResourceGoString(b.Icon)
 				if b.Importance == widget.MediumImportance && b.Alignment == widget.ButtonAlignCenter {
-					return widgetRef(props[obj], defs, fmt.Sprintf("widget.NewButtonWithIcon(\"%s\", %s, %s)", escapeLabel(b.Text), icon, action))
+					return widgetRef(props[obj], defs, fmt.Sprintf("widget.NewButtonWithIcon(%s, %s, %s)", text, icon, action))
 				}
 
-				return widgetRef(props[obj], defs, fmt.Sprintf("&widget.Button{Text: \"%s\", Importance: %d, Icon: %s, Alignment: %d, OnTapped: %s}",
-					escapeLabel(b.Text), b.Importance, icon, b.Alignment, action))
+				return widgetRef(props[obj], defs, fmt.Sprintf("&widget.Button{Text: %s, Importance: %d, Icon: %s, Alignment: %d, OnTapped: %s}",
+					text, b.Importance, icon, b.Alignment, action))
 			},
 			Packages: func(obj fyne.CanvasObject) []string {
 				b := obj.(*widget.Button)
@@ -186,7 +188,7 @@ func initWidgets() {
 			},
 			Gostring: func(obj fyne.CanvasObject, props map[fyne.CanvasObject]map[string]string, defs map[string]string) string {
 				link := obj.(*widget.Hyperlink)
-				return widgetRef(props[obj], defs, fmt.Sprintf(`widget.NewHyperlink("%s", %#v)`, escapeLabel(link.Text), link.URL))
+				return widgetRef(props[obj], defs, fmt.Sprintf(`widget.NewHyperlink(%s, %#v)`, TextGoString(props[obj], "Text", link.Text), link.URL))
 			},
 			Packages: func(_ fyne.CanvasObject) []string {
 				return []string{"net/url"}
@@ -217,8 +219,8 @@ func initWidgets() {
 			},
 			Gostring: func(obj fyne.CanvasObject, props map[fyne.CanvasObject]map[string]string, defs map[string]string) string {
 				c := obj.(*widget.Card)
-				return widgetRef(props[obj], defs, fmt.Sprintf("widget.NewCard(\"%s\", \"%s\", widget.NewLabel(\"Content here\"))",
-					escapeLabel(c.Title), escapeLabel(c.Subtitle)))
+				return widgetRef(props[obj], defs, fmt.Sprintf("widget.NewCard(%s, %s, widget.NewLabel(\"Content here\"))",
+					TextGoString(props[obj], "Title", c.Title), TextGoString(props[obj], "Subtitle", c.Subtitle)))
 			},
 		},
 		"*widget.Entry": {
@@ -249,8 +251,8 @@ func initWidgets() {
 			Gostring: func(obj fyne.CanvasObject, props map[fyne.CanvasObject]map[string]string, defs map[string]string) string {
 				l := obj.(*widget.Entry)
 				return widgetRef(props[obj], defs,
-					fmt.Sprintf("&widget.Entry{Text: \"%s\", PlaceHolder: \"%s\", MultiLine: %t, Password: %t}",
-						escapeLabel(l.Text), escapeLabel(l.PlaceHolder), l.MultiLine, l.Password))
+					fmt.Sprintf("&widget.Entry{Text: \"%s\", PlaceHolder: %s, MultiLine: %t, Password: %t}",
+						escapeLabel(l.Text), TextGoString(props[obj], "PlaceHolder", l.PlaceHolder), l.MultiLine, l.Password))
 			},
 		},
 		"*widget.Icon": {
@@ -269,7 +271,7 @@ func initWidgets() {
 			Gostring: func(obj fyne.CanvasObject, props map[fyne.CanvasObject]map[string]string, defs map[string]string) string {
 				i := obj.(*widget.Icon)
 
-				res := "theme." + IconName(i.Resource) + "()"
+				res := ResourceGoString(i.Resource)
 				return widgetRef(props[obj], defs, fmt.Sprintf("widget.NewIcon(%s)", res))
 			},
 			Packages: func(obj fyne.CanvasObject) []string {
@@ -365,6 +367,7 @@ func initWidgets() {
 			},
 			Gostring: func(obj fyne.CanvasObject, props map[fyne.CanvasObject]map[string]string, defs map[string]string) string {
 				l := obj.(*widget.Label)
+				text := TextGoString(props[obj], "Text", l.Text)
 				if l.Alignment != fyne.TextAlignLeading || l.Wrapping != fyne.TextWrapOff {
 					style := ""
 					if l.TextStyle.Bold || l.TextStyle.Italic || l.TextStyle.Monospace {
@@ -372,15 +375,15 @@ func initWidgets() {
 					}
 
 					return widgetRef(props[obj], defs,
-						fmt.Sprintf("&widget.Label{Text: \"%s\"%s, Alignment: %d, Wrapping: %d}", escapeLabel(l.Text), style, l.Alignment, l.Wrapping))
+						fmt.Sprintf("&widget.Label{Text: %s%s, Alignment: %d, Wrapping: %d}", text, style, l.Alignment, l.Wrapping))
 				}
 
 				if l.TextStyle.Bold || l.TextStyle.Italic || l.TextStyle.Monospace {
 					return widgetRef(props[obj], defs,
-						fmt.Sprintf("widget.NewLabelWithStyle(\"%s\", %d, %#v)", escapeLabel(l.Text), l.Alignment, l.TextStyle))
+						fmt.Sprintf("widget.NewLabelWithStyle(%s, %d, %#v)", text, l.Alignment, l.TextStyle))
 				}
 				return widgetRef(props[obj], defs,
-					fmt.Sprintf("widget.NewLabel(\"%s\")", escapeLabel(l.Text)))
+					fmt.Sprintf("widget.NewLabel(%s)", text))
 			},
 		},
 		"*widget.RichText": {
@@ -454,7 +457,7 @@ func initWidgets() {
 			Gostring: func(obj fyne.CanvasObject, props map[fyne.CanvasObject]map[string]string, defs map[string]string) string {
 				c := obj.(*widget.Check)
 				return widgetRef(props[obj], defs,
-					fmt.Sprintf("widget.NewCheck(\"%s\", func(b bool) {})", escapeLabel(c.Text)))
+					fmt.Sprintf("widget.NewCheck(%s, func(b bool) {})", TextGoString(props[obj], "Text", c.Text)))
 			},
 		},
 		"*widget.RadioGroup": {
@@ -613,22 +616,7 @@ func initWidgets() {
 					"widget.NewMenu(fyne.NewMenu(\"Menu Name\", fyne.NewMenuItem(\"Item 1\", func() {}), fyne.NewMenuItem(\"Item 2\", func() {}), fyne.NewMenuItem(\"Item 3\", func() {})))")
 			},
 		},
-		"*widget.Form": {
-			Name: "Form",
-			Create: func() fyne.CanvasObject {
-				f := widget.NewForm(widget.NewFormItem("Username", widget.NewEntry()), widget.NewFormItem("Password", widget.NewPasswordEntry()))
-				f.OnSubmit = func() {}
-				f.OnCancel = func() {}
-				return f
-			},
-			Edit: func(obj fyne.CanvasObject, _ map[string]string, _ func([]*widget.FormItem), _ func()) []*widget.FormItem {
-				return []*widget.FormItem{}
-			},
-			Gostring: func(obj fyne.CanvasObject, props map[fyne.CanvasObject]map[string]string, defs map[string]string) string {
-				return widgetRef(props[obj], defs,
-					"&widget.Form{Items: []*widget.FormItem{widget.NewFormItem(\"Username\", widget.NewEntry()), widget.NewFormItem(\"Password\", widget.NewPasswordEntry())}, OnSubmit: func() {}, OnCancel: func() {}}")
-			},
-		},
+		"*widget.Form": formInfo(),
 		"*widget.MultiLineEntry": {
 			Name: "Multi Line Entry",
 			Create: func() fyne.CanvasObject {
@@ -751,7 +739,7 @@ func initWidgets() {
 			Gostring: func(obj fyne.CanvasObject, props map[fyne.CanvasObject]map[string]string, defs map[string]string) string {
 				to := obj.(*widget.TextGrid)
 				return widgetRef(props[obj], defs,
-					fmt.Sprintf("widget.NewTextGrid(\"%s\")", escapeLabel(to.Text())))
+					fmt.Sprintf("widget.NewTextGridFromString(\"%s\")", escapeLabel(to.Text())))
 			},
 		},
 		"*widget.Toolbar": {
@@ -917,6 +905,7 @@ map[string][]string{
 			},
 		},
 	}
+	Widgets[IncludeClass] = includeInfo()
 
 	WidgetNames = extractNames(Widgets)
 	CollectionNames = extractNames(Collections)
@@ -945,6 +934,15 @@ func extractNames(in map[string]WidgetInfo) []string {
 	return widgetNamesFromData
 }
 
+// actionCode returns the Go code for an action of an object, or an empty function if it is not set.
+func actionCode(props map[string]string, action string) string {
+	if code := props[action]; code != "" {
+		return code
+	}
+
+	return "func() {}"
+}
+
 func widgetRef(props map[string]string, defs map[string]string, code string) string {
 	if name, ok := props["name"]; ok && name != "" {
 		defs[name] = code
@@ -958,12 +956,61 @@ func widgetRef(props map[string]string, defs map[string]string, code string) str
 func InitOnce() {
 	once.Do(func() {
 		initIcons()
+		initColors()
 		initGraphics()
 		initContainers()
 		initWidgets()
 	})
 }
 
+// Register adds a widget type to the catalogue, replacing any existing definition of the class.
+// Types that have children are listed with the containers, all others with the widgets.
+// An error is returned, and the catalogue left unchanged, if the class or name is empty or `Create` is missing.
+func Register(clazz string, info WidgetInfo) error {
+	InitOnce()
+
+	switch {
+	case clazz == "":
+		return errors.New("widget class must not be empty")
+	case info.Name == "":
+		return fmt.Errorf("widget %s must have a name", clazz)
+	case info.Create == nil:
+		return fmt.Errorf("widget %s must have a Create function", clazz)
+	}
+
+	removeClass(clazz)
+	if info.IsContainer() {
+		Containers[clazz] = info
+	} else {
+		Widgets[clazz] = info
+	}
+
+	extractAllNames()
+	return nil
+}
+
+// Unregister removes a widget type from the catalogue.
+func Unregister(clazz string) {
+	InitOnce()
+
+	removeClass(clazz)
+	extractAllNames()
+}
+
+func removeClass(clazz string) {
+	delete(Widgets, clazz)
+	delete(Collections, clazz)
+	delete(Containers, clazz)
+	delete(Graphics, clazz)
+}
+
+func extractAllNames() {
+	WidgetNames = extractNames(Widgets)
+	CollectionNames = extractNames(Collections)
+	ContainerNames = extractNames(Containers)
+	GraphicsNames = extractNames(Graphics)
+}
+
 // Lookup returns the [WidgetInfo] for the given widget type
 func Lookup(clazz string) *WidgetInfo {
 	if match, ok := Widgets[clazz]; ok {
@@ -11,10 +11,15 @@ import (
 	"fyne.io/fyne/v2/app"
 	"fyne.io/fyne/v2/container"
 	"fyne.io/fyne/v2/storage"
+
+	"github.com/fyne-io/defyne/internal/guibuilder"
+	"github.com/fyne-io/defyne/pkg/gui"
 )
 
 func (d *defyne) setProject(u fyne.URI) {
 	d.projectRoot = u
+	gui.SetProjectRoot(u)
+	guibuilder.SetIncludeOpener(d.openEditor)
 
 	content := container.NewVSplit(d.makeEditorPanel(), d.makeTerminalPanel())
 	content.Offset = 0.8
@@ -27,6 +32,10 @@ func (d *defyne) setProject(u fyne.URI) {
 }
 
 func main() {
+	if runCommand(os.Args[1:]) {
+		return
+	}
+
 	a := app.NewWithID("io.fyne.defyne")
 	a.SetIcon(resourceIconPng)
 	w := a.NewWindow("Defyne")
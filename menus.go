@@ -1,6 +1,9 @@
 package main
 
 import (
+	"errors"
+	"fmt"
+	"go/token"
 	"net/url"
 	"os"
 	"os/exec"
@@ -14,6 +17,8 @@ import (
 	"fyne.io/fyne/v2/widget"
 
 	setup "fyne.io/setup/pkg"
+
+	"github.com/fyne-io/defyne/pkg/gui"
 )
 
 func (d *defyne) menuActionNew() {
@@ -62,6 +67,87 @@ func (d *defyne) menuActionNew() {
 		}, d.win)
 }
 
+func (d *defyne) menuActionImportGo() {
+	open := dialog.NewFileOpen(func(r fyne.URIReadCloser, err error) {
+		if err != nil {
+			dialog.ShowError(err, d.win)
+			return
+		}
+		if r == nil {
+			return
+		}
+
+		obj, meta, err := gui.ImportGo(r, "")
+		_ = r.Close()
+		if err != nil {
+			dialog.ShowError(err, d.win)
+			return
+		}
+
+		dir, err := storage.Parent(r.URI())
+		if err != nil {
+			dialog.ShowError(err, d.win)
+			return
+		}
+		var sources []string
+		if dir.Scheme() == "file" {
+			sources, _ = gui.PackageSources(dir.Path())
+		}
+
+		input := widget.NewEntry()
+		input.SetText(strings.TrimSuffix(r.URI().Name(), r.URI().Extension()))
+		input.Validator = func(name string) error {
+			if !token.IsIdentifier(name) {
+				return errors.New("the name must be a Go identifier")
+			}
+			if clashes := gui.GeneratedClashes(name, sources...); len(clashes) > 0 {
+				return fmt.Errorf("%s.gui.go would declare %s again", name, strings.Join(clashes, ", "))
+			}
+			return nil
+		}
+		item := widget.NewFormItem("Design name", input)
+		item.HintText = "The Go code of the design is generated in the same package"
+		dialog.ShowForm("Import Go UI", "Import", "Cancel", []*widget.FormItem{item}, func(ok bool) {
+			if ok {
+				d.writeImportedDesign(dir, input.Text, obj, meta)
+			}
+		}, d.win)
+	}, d.win)
+	open.SetFilter(storage.NewExtensionFileFilter([]string{".go"}))
+	if dir, err := storage.ListerForURI(d.projectRoot); err == nil {
+		open.SetLocation(dir)
+	}
+	open.Show()
+}
+
+// writeImportedDesign saves an imported object tree as a new design in the directory and opens it.
+func (d *defyne) writeImportedDesign(dir fyne.URI, name string, obj fyne.CanvasObject, meta map[fyne.CanvasObject]map[string]string) {
+	uri, err := storage.Child(dir, name+".gui.json")
+	if err != nil {
+		dialog.ShowError(err, d.win)
+		return
+	}
+	if exists, _ := storage.Exists(uri); exists {
+		dialog.ShowInformation("File exists", "The design "+uri.Name()+" already exists", d.win)
+		return
+	}
+
+	w, err := storage.Writer(uri)
+	if err != nil {
+		dialog.ShowError(err, d.win)
+		return
+	}
+	err = gui.EncodeObject(obj, meta, w)
+	_ = w.Close()
+	if err != nil {
+		dialog.ShowError(err, d.win)
+		return
+	}
+
+	d.openEditor(uri)
+	d.fileTree.Refresh()
+}
+
 func (d *defyne) menuActionRunProject() {
 	pwd, _ := os.Getwd()
 	os.Chdir(d.projectRoot.Path())
@@ -127,6 +213,7 @@ func (d *defyne) makeMenu() *fyne.MainMenu {
 			fyne.NewMenuItem("Open Project...", d.showProjectSelect),
 			fyne.NewMenuItemSeparator(),
 			fyne.NewMenuItem("New File...", d.menuActionNew),
+			fyne.NewMenuItem("Import Go UI...", d.menuActionImportGo),
 			fyne.NewMenuItemSeparator(),
 			fyne.NewMenuItem("Save", d.menuActionSave),
 			fyne.NewMenuItemSeparator(),
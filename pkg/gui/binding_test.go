@@ -0,0 +1,127 @@
+package gui
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/widget"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func boundDesign() (fyne.CanvasObject, map[fyne.CanvasObject]map[string]string) {
+	l := widget.NewLabel("Welcome")
+	e := widget.NewEntry()
+	s := widget.NewSlider(0, 10)
+	s.Value = 2.5
+	c := container.NewVBox(l, e, s)
+
+	return c, map[fyne.CanvasObject]map[string]string{
+		c: {"layout": "VBox", "dir": "vertical"},
+		l: {"binding": "title"},
+		e: {"binding": "title"},
+		s: {"name": "volume", "binding": "level"},
+	}
+}
+
+func TestBinding_EncodeDecode(t *testing.T) {
+	obj, meta := boundDesign()
+
+	var buf bytes.Buffer
+	require.Nil(t, EncodeObject(obj, meta, &buf))
+	assert.Contains(t, buf.String(), `"Binding": "level"`)
+
+	obj2, meta2, err := DecodeObject(&buf)
+	require.Nil(t, err)
+	c := obj2.(*fyne.Container)
+	assert.Equal(t, "title", meta2[c.Objects[0]]["binding"])
+	assert.Equal(t, "title", meta2[c.Objects[1]]["binding"])
+	assert.Equal(t, "level", meta2[c.Objects[2]]["binding"])
+	assert.Equal(t, "volume", meta2[c.Objects[2]]["name"])
+
+	_, _, err = DecodeObject(strings.NewReader(`{"Version": 1, "Object": {"Type": "*widget.Separator", "Binding": "x", "Struct": {}}}`))
+	require.NotNil(t, err)
+	assert.Equal(t, "Binding: type *widget.Separator does not support data binding", err.Error())
+}
+
+func TestBinding_ExportGo(t *testing.T) {
+	obj, meta := boundDesign()
+
+	var buf bytes.Buffer
+	require.Nil(t, ExportGo(obj, meta, "main", &buf))
+	code := buf.String()
+	assert.Contains(t, code, `"fyne.io/fyne/v2/data/binding"`)
+	assert.Contains(t, code, "title  binding.String\n")
+	assert.Contains(t, code, "level  binding.Float\n")
+	assert.Contains(t, code, `_ = g.title.Set("Welcome")`)
+	assert.Contains(t, code, `_ = g.level.Set(2.5)`)
+	assert.Contains(t, code, "widget.NewLabelWithData(g.title)")
+	assert.Contains(t, code, "widget.NewEntryWithData(g.title)")
+	assert.Contains(t, code, "g.volume = widget.NewSliderWithData(0, 10, g.level)")
+	assert.Equal(t, 1, strings.Count(code, "g.title = binding.NewString()"))
+
+	imported, meta2, err := ImportGo(&buf, "")
+	require.Nil(t, err)
+	c := imported.(*fyne.Container)
+	assert.Equal(t, "title", meta2[c.Objects[0]]["binding"])
+	assert.Equal(t, "level", meta2[c.Objects[2]]["binding"])
+	assert.Equal(t, 10.0, c.Objects[2].(*widget.Slider).Max)
+}
+
+func TestBinding_Editor(t *testing.T) {
+	l := widget.NewLabel("Hi")
+	props := map[string]string{}
+	items := EditorFor(l, props, nil, nil)
+
+	bind := items[len(items)-1]
+	assert.Equal(t, "Binding", bind.Text)
+	bind.Widget.(*widget.Entry).SetText("greeting")
+	assert.Equal(t, "greeting", props["binding"])
+	bind.Widget.(*widget.Entry).SetText("")
+	_, ok := props["binding"]
+	assert.False(t, ok)
+
+	items = EditorFor(widget.NewSeparator(), map[string]string{}, nil, nil)
+	assert.Equal(t, 0, len(items))
+}
+
+func TestBinding_Conflicts(t *testing.T) {
+	obj, meta := boundDesign()
+	c := obj.(*fyne.Container)
+	meta[c.Objects[1]]["binding"] = "level" // a String entry bound to the Float of the slider
+
+	var buf bytes.Buffer
+	assert.NotNil(t, ExportGo(obj, meta, "main", &buf))
+	require.Nil(t, EncodeObject(obj, meta, &buf))
+	_, _, err := DecodeObject(&buf)
+	require.NotNil(t, err)
+	assert.Contains(t, err.Error(), "Objects[1].Binding: level is bound to a Float elsewhere")
+
+	meta[c.Objects[1]]["binding"] = "volume" // the name of the slider
+	buf.Reset()
+	err = ExportGo(obj, meta, "main", &buf)
+	require.NotNil(t, err)
+	assert.Equal(t, "binding volume has the same name as an object", err.Error())
+	require.Nil(t, EncodeObject(obj, meta, &buf))
+	obj2, meta2, err := DecodeObjectLenient(&buf)
+	require.NotNil(t, err)
+	assert.Equal(t, "Objects[1].Binding: volume is the name of an object", err.Error())
+	assert.Empty(t, meta2[obj2.(*fyne.Container).Objects[1]]["binding"])
+
+	delete(meta[c.Objects[1]], "binding")
+	items := EditorForDesign(c.Objects[1], meta, nil, nil)
+	bind := items[len(items)-1].Widget.(*widget.Entry)
+	bind.SetText("level")
+	assert.NotNil(t, bind.Validate())
+	bind.SetText("volume")
+	assert.NotNil(t, bind.Validate())
+	_, ok := meta[c.Objects[1]]["binding"]
+	assert.False(t, ok)
+	bind.SetText("title")
+	assert.Nil(t, bind.Validate())
+	assert.Equal(t, "title", meta[c.Objects[1]]["binding"])
+}
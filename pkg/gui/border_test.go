@@ -0,0 +1,60 @@
+package gui
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/layout"
+	"fyne.io/fyne/v2/test"
+	"fyne.io/fyne/v2/widget"
+
+	"github.com/fyne-io/defyne/internal/guidefs"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBorder_Editor(t *testing.T) {
+	test.NewTempApp(t)
+	content := widget.NewLabel("Content")
+	title := widget.NewLabel("Title")
+	save := widget.NewButton("Save", nil)
+	c := container.NewVBox(content, title, save)
+	meta := map[fyne.CanvasObject]map[string]string{c: {"layout": "VBox"}, title: {"name": "title"}}
+
+	var items []*widget.FormItem
+	items = EditorForDesign(c, meta, func(i []*widget.FormItem) {
+		items = i
+	}, nil)
+	items[0].Widget.(*widget.Select).SetSelected("Border")
+	require.Equal(t, "Top", items[1].Text)
+	assert.Equal(t, []string{"(Empty)", "Label (Content)", "Label (title)", "Button (Save)"},
+		items[1].Widget.(*widget.Select).Options)
+	items[1].Widget.(*widget.Select).SetSelectedIndex(2)
+	items[2].Widget.(*widget.Select).SetSelectedIndex(3)
+
+	assert.Equal(t, "node1", meta[title]["id"])
+	assert.Equal(t, "node1", meta[c]["top"])
+	assert.Equal(t, "node2", meta[save]["id"])
+	assert.Equal(t, "node2", meta[c]["bottom"])
+
+	// the references follow the children when they are moved
+	c.Objects = []fyne.CanvasObject{save, title, content}
+	guidefs.UpdateLayout(c, meta)
+	c.Resize(fyne.NewSize(200, 200))
+	assert.Equal(t, fyne.NewPos(0, 0), title.Position())
+	assert.Equal(t, float32(200)-save.MinSize().Height, save.Position().Y)
+
+	var buf bytes.Buffer
+	require.Nil(t, ExportGo(c, meta, "main", &buf))
+	code := strings.Join(strings.Fields(buf.String()), " ")
+	assert.Contains(t, code, `container.NewBorder( g.title, widget.NewButton("Save", func() {}), nil, nil, widget.NewLabel("Content"))`)
+
+	c.Remove(save)
+	guidefs.UpdateLayout(c, meta)
+	assert.Equal(t, "node1", meta[c]["top"])
+	assert.Equal(t, "", meta[c]["bottom"])
+	assert.IsType(t, layout.NewBorderLayout(nil, nil, nil, nil), c.Layout)
+}
@@ -0,0 +1,84 @@
+package gui
+
+import (
+	"bytes"
+	"image/color"
+	"strings"
+	"testing"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/canvas"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/theme"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestThemeColor_EncodeDecode(t *testing.T) {
+	r := canvas.NewRectangle(theme.Color(theme.ColorNamePrimary))
+	r.StrokeColor = color.White
+	meta := map[fyne.CanvasObject]map[string]string{r: {"FillColor": "primary"}}
+
+	var buf bytes.Buffer
+	require.Nil(t, EncodeObject(r, meta, &buf))
+	assert.Contains(t, buf.String(), `"ThemeColors": {
+      "FillColor": "primary"
+    }`)
+
+	obj, meta2, err := DecodeObject(&buf)
+	require.Nil(t, err)
+	r2 := obj.(*canvas.Rectangle)
+	assert.Equal(t, "primary", meta2[r2]["FillColor"])
+	assert.Equal(t, theme.Color(theme.ColorNamePrimary), r2.FillColor)
+	assert.Equal(t, toNRGBA(color.White), toNRGBA(r2.StrokeColor))
+
+	_, _, err = DecodeObject(strings.NewReader(`{"Version": 1, "Object": {"Type": "*canvas.Rectangle", "ThemeColors": {"CornerRadius": "primary"}, "Struct": {}}}`))
+	require.NotNil(t, err)
+	assert.Equal(t, "ThemeColors.CornerRadius: CornerRadius is not a color field", err.Error())
+}
+
+func TestThemeColor_EncodeThemeIndependent(t *testing.T) {
+	encode := func(variant fyne.ThemeVariant) string {
+		r := canvas.NewRectangle(theme.DefaultTheme().Color(theme.ColorNameForeground, variant))
+		var buf bytes.Buffer
+		require.Nil(t, EncodeObject(r, map[fyne.CanvasObject]map[string]string{r: {"FillColor": "foreground"}}, &buf))
+		return buf.String()
+	}
+
+	light := encode(theme.VariantLight)
+	assert.Equal(t, light, encode(theme.VariantDark))
+	assert.NotContains(t, light, `"FillColor": {`)
+}
+
+func TestThemeColor_ExportGo(t *testing.T) {
+	r := canvas.NewRectangle(theme.Color(theme.ColorNamePrimary))
+	r.StrokeColor = color.Black
+	c := container.NewStack(r)
+	meta := map[fyne.CanvasObject]map[string]string{
+		c: {"layout": "Stack"},
+		r: {"FillColor": "primary"},
+	}
+
+	var buf bytes.Buffer
+	require.Nil(t, ExportGo(c, meta, "main", &buf))
+	code := buf.String()
+	assert.Contains(t, code, "FillColor: theme.Color(theme.ColorNamePrimary)")
+	assert.Contains(t, code, `"fyne.io/fyne/v2/theme"`)
+	assert.Contains(t, code, `"image/color"`)
+
+	obj, meta2, err := ImportGo(&buf, "")
+	require.Nil(t, err)
+	r2 := obj.(*fyne.Container).Objects[0].(*canvas.Rectangle)
+	assert.Equal(t, "primary", meta2[r2]["FillColor"])
+
+	meta[r]["StrokeColor"] = "foreground"
+	buf.Reset()
+	require.Nil(t, ExportGo(c, meta, "main", &buf))
+	assert.Contains(t, buf.String(), "StrokeColor: theme.Color(theme.ColorNameForeground)")
+	assert.NotContains(t, buf.String(), `"image/color"`)
+}
+
+func toNRGBA(c color.Color) color.NRGBA {
+	return color.NRGBAModel.Convert(c).(color.NRGBA)
+}
@@ -0,0 +1,178 @@
+package gui
+
+import (
+	"bytes"
+	"fmt"
+	"go/parser"
+	"go/token"
+	"image/color"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/test"
+
+	"github.com/fyne-io/defyne/internal/guidefs"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	colorType = reflect.TypeOf((*color.Color)(nil)).Elem()
+	objType   = reflect.TypeOf((*fyne.CanvasObject)(nil)).Elem()
+)
+
+// conformanceClasses returns every class that can be created.
+// Tests that register their own types must remove them again, so they are not checked here.
+func conformanceClasses() []string {
+	guidefs.InitOnce()
+
+	var classes []string
+	for _, list := range [][]string{WidgetClassList(), ContainerClassList(), CollectionClassList(), GraphicsClassList()} {
+		classes = append(classes, list...)
+	}
+	return classes
+}
+
+func conformanceMeta(obj fyne.CanvasObject) map[fyne.CanvasObject]map[string]string {
+	if c, ok := obj.(*fyne.Container); ok {
+		return map[fyne.CanvasObject]map[string]string{c: {"layout": "VBox", "dir": "vertical"}}
+	}
+	return nil
+}
+
+func TestConformance(t *testing.T) {
+	for _, class := range conformanceClasses() {
+		t.Run(class, func(t *testing.T) {
+			obj := CreateNew(class)
+			require.NotNil(t, obj)
+			meta := conformanceMeta(obj)
+
+			var buf bytes.Buffer
+			require.Nil(t, EncodeObject(obj, meta, &buf))
+			decoded, _, err := DecodeObject(&buf)
+			require.Nil(t, err)
+			assert.IsType(t, obj, decoded)
+
+			// render both so that state managed by the widgets is set up in the same way
+			w1, w2 := test.NewWindow(obj), test.NewWindow(decoded)
+			defer w1.Close()
+			defer w2.Close()
+			assertFieldsEqual(t, class, reflect.ValueOf(obj), reflect.ValueOf(decoded))
+
+			buf.Reset()
+			require.Nil(t, ExportGo(obj, meta, "main", &buf))
+			_, err = parser.ParseFile(token.NewFileSet(), "gui.go", buf.Bytes(), 0)
+			assert.Nil(t, err)
+		})
+	}
+}
+
+func FuzzDecodeObject(f *testing.F) {
+	for _, class := range conformanceClasses() {
+		obj := CreateNew(class)
+		var buf bytes.Buffer
+		if err := EncodeObject(obj, conformanceMeta(obj), &buf); err == nil {
+			f.Add(buf.Bytes())
+		}
+	}
+	files, _ := filepath.Glob(filepath.Join("testdata", "*", "*.gui.json"))
+	for _, file := range files {
+		if data, err := os.ReadFile(file); err == nil {
+			f.Add(data)
+		}
+	}
+	f.Add([]byte(brokenJSON))
+	f.Add([]byte(`{"Version": -1, "Object": {}}`))
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		obj, _, err := DecodeObject(bytes.NewReader(data))
+		if err == nil && obj == nil {
+			t.Error("no object or error returned")
+		}
+		_, _, _ = DecodeObjectLenient(bytes.NewReader(data))
+	})
+}
+
+// assertFieldsEqual compares the exported fields of two values, ignoring functions.
+// Resources are compared by name and colors by their RGBA value.
+func assertFieldsEqual(t *testing.T, path string, a, b reflect.Value) {
+	if !a.IsValid() || !b.IsValid() {
+		assert.Equal(t, a.IsValid(), b.IsValid(), path)
+		return
+	}
+
+	switch {
+	case a.Type() == resourceType:
+		if a.IsNil() || b.IsNil() {
+			assert.Equal(t, a.IsNil(), b.IsNil(), path)
+			return
+		}
+		assert.Equal(t, guidefs.IconName(a.Interface().(fyne.Resource)), guidefs.IconName(b.Interface().(fyne.Resource)), path)
+		return
+	case a.Type() == colorType:
+		if a.IsNil() || b.IsNil() {
+			assert.Equal(t, a.IsNil(), b.IsNil(), path)
+			return
+		}
+		assert.Equal(t, color.NRGBAModel.Convert(a.Interface().(color.Color)),
+			color.NRGBAModel.Convert(b.Interface().(color.Color)), path)
+		return
+	}
+
+	switch a.Kind() {
+	case reflect.Func, reflect.Chan:
+		return
+	case reflect.Interface, reflect.Ptr:
+		if a.IsNil() || b.IsNil() {
+			assert.Equal(t, a.IsNil(), b.IsNil(), path)
+			return
+		}
+		if a.Kind() == reflect.Interface && a.Type() == objType {
+			assert.Equal(t, a.Elem().Type(), b.Elem().Type(), path)
+		}
+		assertFieldsEqual(t, path, a.Elem(), b.Elem())
+	case reflect.Struct:
+		if !hasExportedFields(a.Type()) {
+			assert.Equal(t, fmt.Sprint(a.Interface()), fmt.Sprint(b.Interface()), path)
+			return
+		}
+		for i := 0; i < a.NumField(); i++ {
+			f := a.Type().Field(i)
+			if f.Anonymous && f.Type.Kind() == reflect.Struct {
+				if hasExportedFields(f.Type) {
+					assertFieldsEqual(t, path, a.Field(i), b.Field(i))
+				}
+			} else if f.IsExported() {
+				assertFieldsEqual(t, path+"."+f.Name, a.Field(i), b.Field(i))
+			}
+		}
+	case reflect.Slice, reflect.Array:
+		if !assert.Equal(t, a.Len(), b.Len(), path) {
+			return
+		}
+		for i := 0; i < a.Len(); i++ {
+			assertFieldsEqual(t, indexPath(path, i), a.Index(i), b.Index(i))
+		}
+	case reflect.Map:
+		if !assert.Equal(t, a.Len(), b.Len(), path) {
+			return
+		}
+		for _, k := range a.MapKeys() {
+			assertFieldsEqual(t, path+"["+k.String()+"]", a.MapIndex(k), b.MapIndex(k))
+		}
+	default:
+		assert.Equal(t, a.Interface(), b.Interface(), path)
+	}
+}
+
+func hasExportedFields(t reflect.Type) bool {
+	for _, f := range reflect.VisibleFields(t) {
+		if f.IsExported() {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,199 @@
+package gui
+
+import (
+	"fmt"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/widget"
+
+	"github.com/fyne-io/defyne/internal/guidefs"
+)
+
+// The types of dialog that a design can be shown in, each uses the matching constructor of the Fyne dialog package.
+const (
+	// DialogCustom shows the design with a dismiss button, using `dialog.NewCustom`.
+	DialogCustom = "Custom"
+	// DialogConfirm shows the design with confirm and dismiss buttons, using `dialog.NewCustomConfirm`.
+	DialogConfirm = "Confirm"
+	// DialogForm shows the items of a design that is a `*widget.Form`, using `dialog.NewForm`.
+	DialogForm = "Form"
+)
+
+// dialogPrefix starts the keys that store the dialog of a design, in the metadata of the root object.
+const dialogPrefix = "dialog."
+
+// DialogTypes lists the types of dialog that a design can be shown in.
+var DialogTypes = []string{DialogCustom, DialogConfirm, DialogForm}
+
+// dialogActions lists the callbacks of a dialog, "OnConfirmed" is a `func(bool)` and "OnClosed" a `func()`.
+var dialogActions = []string{"OnConfirmed", "OnClosed"}
+
+// Dialog describes how a design is shown in a dialog over a parent window, rather than as window content.
+type Dialog struct {
+	Type    string
+	Title   string
+	Dismiss string `json:",omitempty"`
+	Confirm string `json:",omitempty"`
+
+	// Actions holds the Go code of the "OnConfirmed" and "OnClosed" callbacks, like the actions of an object.
+	Actions map[string]string `json:",omitempty"`
+}
+
+// DialogFor returns the dialog that a design is shown in, or nil if it is shown as window content.
+func DialogFor(obj fyne.CanvasObject, meta map[fyne.CanvasObject]map[string]string) *Dialog {
+	props := meta[obj]
+	if props[dialogPrefix+"Type"] == "" {
+		return nil
+	}
+
+	d := &Dialog{Type: props[dialogPrefix+"Type"], Title: props[dialogPrefix+"Title"],
+		Dismiss: props[dialogPrefix+"Dismiss"], Confirm: props[dialogPrefix+"Confirm"]}
+	for _, action := range dialogActions {
+		if code := props[dialogPrefix+action]; code != "" {
+			if d.Actions == nil {
+				d.Actions = make(map[string]string)
+			}
+			d.Actions[action] = code
+		}
+	}
+	return d
+}
+
+// SetDialog sets the dialog that a design is shown in, the dialog is stored in the metadata of the root object.
+// Passing nil shows the design as window content again.
+func SetDialog(obj fyne.CanvasObject, meta map[fyne.CanvasObject]map[string]string, d *Dialog) {
+	props, ok := meta[obj]
+	if !ok {
+		props = make(map[string]string)
+		meta[obj] = props
+	}
+	for k := range props {
+		if strings.HasPrefix(k, dialogPrefix) {
+			delete(props, k)
+		}
+	}
+	if d == nil {
+		return
+	}
+
+	set := func(key, value string) {
+		if value != "" {
+			props[dialogPrefix+key] = value
+		}
+	}
+	set("Type", d.Type)
+	set("Title", d.Title)
+	set("Dismiss", d.Dismiss)
+	set("Confirm", d.Confirm)
+	for action, code := range d.Actions {
+		set(action, code)
+	}
+}
+
+// DismissText returns the text of the button that closes the dialog, or the default for its type if none is set.
+func (d *Dialog) DismissText() string {
+	switch {
+	case d.Dismiss != "":
+		return d.Dismiss
+	case d.Type == DialogCustom:
+		return "Close"
+	}
+	return "Cancel"
+}
+
+// ConfirmText returns the text of the confirm button, or the default if none is set.
+// Custom dialogs do not have a confirm button.
+func (d *Dialog) ConfirmText() string {
+	if d.Confirm != "" {
+		return d.Confirm
+	}
+	return "OK"
+}
+
+// isDocumentKey returns true for the metadata keys of a root object that are stored as fields of the document.
+func isDocumentKey(k string) bool {
+	return k == testSizeKey || k == windowKey || k == handlersKey || strings.HasPrefix(k, dialogPrefix)
+}
+
+func (d *decoder) decodeDialog(obj fyne.CanvasObject, m map[string]interface{}) {
+	dlg := &Dialog{}
+	if typ, ok := d.stringValue(m["Type"], "Dialog.Type"); ok {
+		switch {
+		case !containsString(DialogTypes, typ):
+			d.fail("Dialog.Type", "unknown dialog type %q", typ)
+			return
+		case typ == DialogForm:
+			if _, ok := obj.(*widget.Form); !ok {
+				d.fail("Dialog.Type", "a form dialog must contain a *widget.Form")
+				return
+			}
+		}
+		dlg.Type = typ
+	} else if m["Type"] == nil {
+		d.fail("Dialog.Type", "missing dialog type")
+		return
+	}
+
+	dlg.Title, _ = d.stringValue(m["Title"], "Dialog.Title")
+	dlg.Dismiss, _ = d.stringValue(m["Dismiss"], "Dialog.Dismiss")
+	dlg.Confirm, _ = d.stringValue(m["Confirm"], "Dialog.Confirm")
+	if set, ok := d.mapValue(m["Actions"], "Dialog.Actions"); ok {
+		for _, k := range sortedKeys(set) {
+			path := joinPath("Dialog.Actions", k)
+			code, ok := d.stringValue(set[k], path)
+			if !ok {
+				continue
+			}
+			if !containsString(dialogActions, k) {
+				d.fail(path, "unknown dialog action %s", k)
+				continue
+			}
+
+			if dlg.Actions == nil {
+				dlg.Actions = make(map[string]string)
+			}
+			dlg.Actions[k] = code
+		}
+	}
+	SetDialog(obj, d.meta, dlg)
+}
+
+// dialogGoString returns the Go code of a function that shows the design in its dialog, such as
+// `showSettingsDialog(parent fyne.Window)` for a design named "settings".
+func dialogGoString(dlg *Dialog, name string) string {
+	_, create := guidefs.IncludeGoNames(name + ".gui.json")
+	funcName := "showDialog"
+	if name != "main" {
+		funcName = "show" + strings.ToUpper(name[0:1]) + name[1:] + "Dialog"
+	}
+
+	dismiss, confirm := dlg.DismissText(), dlg.ConfirmText()
+	callback := dlg.Actions["OnConfirmed"]
+	if callback == "" {
+		callback = "nil"
+	}
+
+	var show string
+	switch dlg.Type {
+	case DialogConfirm:
+		show = fmt.Sprintf("dialog.NewCustomConfirm(%q, %q, %q, g.makeUI(), %s, parent)", dlg.Title, confirm, dismiss, callback)
+	case DialogForm:
+		show = fmt.Sprintf("dialog.NewForm(%q, %q, %q, g.makeUI().(*widget.Form).Items, %s, parent)", dlg.Title, confirm, dismiss, callback)
+	default:
+		show = fmt.Sprintf("dialog.NewCustom(%q, %q, g.makeUI(), parent)", dlg.Title, dismiss)
+	}
+	closed := ""
+	if code := dlg.Actions["OnClosed"]; code != "" {
+		closed = "\td.SetOnClosed(" + code + ")\n"
+	}
+
+	return fmt.Sprintf(`
+// %s shows the design in a dialog over the parent window.
+func %s(parent fyne.Window) {
+	g := %s
+	d := %s
+%s	d.Show()
+}
+`, funcName, funcName, create, show, closed)
+}
@@ -0,0 +1,111 @@
+package gui
+
+import (
+	"bytes"
+	"errors"
+	"go/parser"
+	"go/token"
+	"strings"
+	"testing"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/widget"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func dialogDesign() (fyne.CanvasObject, map[fyne.CanvasObject]map[string]string) {
+	name := widget.NewEntry()
+	c := container.NewVBox(widget.NewLabel("Your name"), name)
+	meta := map[fyne.CanvasObject]map[string]string{
+		c:    {"layout": "VBox", "dir": "vertical"},
+		name: {"name": "nameEntry"},
+	}
+	SetDialog(c, meta, &Dialog{Type: DialogConfirm, Title: "Settings", Confirm: "Save",
+		Actions: map[string]string{"OnConfirmed": "g.save", "OnClosed": "func() {}"}})
+	return c, meta
+}
+
+func TestDialog_EncodeDecode(t *testing.T) {
+	obj, meta := dialogDesign()
+
+	var buf bytes.Buffer
+	require.Nil(t, EncodeObject(obj, meta, &buf))
+	assert.Contains(t, buf.String(), `"Dialog": {`)
+	assert.NotContains(t, buf.String(), dialogPrefix)
+
+	obj2, meta2, err := DecodeObject(&buf)
+	require.Nil(t, err)
+	assert.Equal(t, DialogFor(obj, meta), DialogFor(obj2, meta2))
+	assert.Equal(t, "Save", DialogFor(obj2, meta2).ConfirmText())
+	assert.Equal(t, "Cancel", DialogFor(obj2, meta2).DismissText())
+
+	SetDialog(obj2, meta2, nil)
+	assert.Nil(t, DialogFor(obj2, meta2))
+	assert.Equal(t, "VBox", meta2[obj2]["layout"])
+}
+
+func TestDialog_DecodeErrors(t *testing.T) {
+	for doc, problem := range map[string]DecodeProblem{
+		`{"Version": 1, "Dialog": {"Type": "Popup"}, "Object": {"Type": "*widget.Label", "Struct": {}}}`: {
+			Path: "Dialog.Type", Message: `unknown dialog type "Popup"`},
+		`{"Version": 1, "Dialog": {"Type": "Form"}, "Object": {"Type": "*widget.Label", "Struct": {}}}`: {
+			Path: "Dialog.Type", Message: "a form dialog must contain a *widget.Form"},
+		`{"Version": 1, "Dialog": {"Title": "Hi"}, "Object": {"Type": "*widget.Label", "Struct": {}}}`: {
+			Path: "Dialog.Type", Message: "missing dialog type"},
+		`{"Version": 1, "Dialog": {"Type": "Custom", "Actions": {"OnTapped": "f"}}, "Object": {"Type": "*widget.Label", "Struct": {}}}`: {
+			Path: "Dialog.Actions.OnTapped", Message: "unknown dialog action OnTapped"},
+	} {
+		_, _, err := DecodeObject(strings.NewReader(doc))
+		var decodeErr *DecodeError
+		require.True(t, errors.As(err, &decodeErr), doc)
+		assert.Equal(t, []DecodeProblem{problem}, decodeErr.Problems)
+	}
+}
+
+func TestDialog_ExportGo(t *testing.T) {
+	obj, meta := dialogDesign()
+
+	var buf bytes.Buffer
+	require.Nil(t, ExportGo(obj, meta, "settings", &buf))
+	_, err := parser.ParseFile(token.NewFileSet(), "settings.go", buf.Bytes(), 0)
+	require.Nil(t, err)
+	code := strings.Join(strings.Fields(buf.String()), " ")
+	assert.Contains(t, code, `"fyne.io/fyne/v2/dialog"`)
+	assert.Contains(t, code, "func showSettingsDialog(parent fyne.Window) {")
+	assert.Contains(t, code, `d := dialog.NewCustomConfirm("Settings", "Save", "Cancel", g.makeUI(), g.save, parent)`)
+	assert.Contains(t, code, "d.SetOnClosed(func() {})")
+
+	f, formMeta := formDesign()
+	SetDialog(f, formMeta, &Dialog{Type: DialogForm, Title: "Login"})
+	buf.Reset()
+	require.Nil(t, ExportGo(f, formMeta, "login", &buf))
+	code = strings.Join(strings.Fields(buf.String()), " ")
+	assert.Contains(t, code, `d := dialog.NewForm("Login", "OK", "Cancel", g.makeUI().(*widget.Form).Items, nil, parent)`)
+	assert.NotContains(t, code, "SetOnClosed")
+
+	label := widget.NewLabel("Done")
+	labelMeta := map[fyne.CanvasObject]map[string]string{}
+	SetDialog(label, labelMeta, &Dialog{Type: DialogCustom, Title: "About"})
+	buf.Reset()
+	require.Nil(t, ExportGoPreview(label, labelMeta, &buf))
+	code = strings.Join(strings.Fields(buf.String()), " ")
+	assert.Contains(t, code, `d := dialog.NewCustom("About", "Close", g.makeUI(), parent)`)
+	assert.Contains(t, code, "showDialog(myWindow)")
+	assert.NotContains(t, code, "SetContent")
+}
+
+func TestDialog_Schema(t *testing.T) {
+	schema := loadSchema(t)
+	obj, meta := dialogDesign()
+
+	var buf bytes.Buffer
+	require.Nil(t, EncodeObject(obj, meta, &buf))
+	assert.Empty(t, validateSchema(schema, decodeJSON(t, buf.Bytes())))
+
+	problems := validateSchema(schema, decodeJSON(t, []byte(
+		`{"Version": 1, "Dialog": {"Type": "Popup"}, "Object": {"Type": "*widget.Label", "Struct": {}}}`)))
+	assert.Contains(t, strings.Join(problems, "\n"), "/Dialog/Type: value not in enum")
+}
@@ -22,6 +22,13 @@ func CreateNew(name string) fyne.CanvasObject {
 // EditorFor returns an array of FormItems for editing, taking the widget, properties, callback to refresh the form items,
 // and an optional callback that fires after changes to the widget.
 func EditorFor(o fyne.CanvasObject, props map[string]string, refresh func([]*widget.FormItem), onchanged func()) []*widget.FormItem {
+	return EditorForDesign(o, map[fyne.CanvasObject]map[string]string{o: props}, refresh, onchanged)
+}
+
+// EditorForDesign returns the FormItems for editing an object like `EditorFor`, taking the metadata of the whole design.
+// Editors that refer to other objects, such as the edges of a border container, store the references in the metadata.
+func EditorForDesign(o fyne.CanvasObject, meta map[fyne.CanvasObject]map[string]string, refresh func([]*widget.FormItem),
+	onchanged func()) []*widget.FormItem {
 	guidefs.InitOnce()
 
 	_, clazz := getTypeOf(o)
@@ -30,11 +37,23 @@ func EditorFor(o fyne.CanvasObject, props map[string]string, refresh func([]*wid
 		onchanged = func() {}
 	}
 
-	if match := guidefs.Lookup(clazz); match != nil {
-		return match.Edit(o, props, refresh, onchanged)
+	match := guidefs.Lookup(clazz)
+	if match == nil || (match.Edit == nil && match.EditWithMeta == nil) {
+		return nil
 	}
 
-	return nil
+	props := meta[o]
+	var items []*widget.FormItem
+	if match.EditWithMeta != nil {
+		items = match.EditWithMeta(o, meta, refresh, onchanged)
+	} else {
+		items = match.Edit(o, props, refresh, onchanged)
+	}
+	items = append(items, guidefs.NewTranslationFormItems(o, props, onchanged)...)
+	if bind := guidefs.NewBindingFormItem(o, meta, onchanged); bind != nil {
+		items = append(items, bind)
+	}
+	return items
 }
 
 // GoStringFor generates the Go code for the given widget
@@ -42,12 +61,7 @@ func GoStringFor(o fyne.CanvasObject, props map[fyne.CanvasObject]map[string]str
 	guidefs.InitOnce()
 
 	name := reflect.TypeOf(o).String()
-
-	if match := guidefs.Lookup(name); match != nil {
-		return match.Gostring(o, props, defs)
-	}
-
-	return ""
+	return guidefs.GoString(name, o, props, defs)
 }
 
 func getTypeOf(o fyne.CanvasObject) (string, string) {
@@ -0,0 +1,38 @@
+package gui
+
+import (
+	"fmt"
+	"strings"
+)
+
+// DecodeProblem describes a single value that could not be decoded.
+// The Path locates the value in the JSON object, for example `Objects[2].Struct.Leading.Type`.
+type DecodeProblem struct {
+	Path, Message string
+}
+
+func (p DecodeProblem) String() string {
+	if p.Path == "" {
+		return p.Message
+	}
+
+	return p.Path + ": " + p.Message
+}
+
+// DecodeError is returned when a document contains values that could not be decoded.
+// It lists every problem that was found, in the order they were encountered.
+type DecodeError struct {
+	Problems []DecodeProblem
+}
+
+func (e *DecodeError) Error() string {
+	if len(e.Problems) == 1 {
+		return e.Problems[0].String()
+	}
+
+	lines := make([]string, len(e.Problems))
+	for i, p := range e.Problems {
+		lines[i] = p.String()
+	}
+	return fmt.Sprintf("%d problems decoding GUI:\n%s", len(e.Problems), strings.Join(lines, "\n"))
+}
@@ -0,0 +1,136 @@
+package gui
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/widget"
+
+	"github.com/fyne-io/defyne/internal/guidefs"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const brokenJSON = `{
+  "Version": 1,
+  "Object": {
+    "Type": "*fyne.Container",
+    "Layout": "VBox",
+    "Objects": [
+      {
+        "Type": "*widget.Label",
+        "Struct": {"Text": "Hi"}
+      },
+      {
+        "Type": "*widget.Missing"
+      },
+      {
+        "Type": "*container.Split",
+        "Struct": {
+          "Leading": {"Type": 5},
+          "Trailing": {"Type": "*widget.Label", "Struct": {"Text": "There", "TextStyle": {"Bold": "yes"}}}
+        }
+      },
+      {
+        "Type": "*widget.Button",
+        "Struct": {"Text": 7, "Icon": "NotAnIcon"}
+      }
+    ]
+  }
+}`
+
+func TestDecodeObject_Problems(t *testing.T) {
+	obj, meta, err := DecodeObject(strings.NewReader(brokenJSON))
+	assert.Nil(t, obj)
+	assert.Nil(t, meta)
+	require.NotNil(t, err)
+
+	var decodeErr *DecodeError
+	require.True(t, errors.As(err, &decodeErr))
+	assert.Equal(t, []DecodeProblem{
+		{Path: "Objects[1].Type", Message: `unknown type "*widget.Missing"`},
+		{Path: "Objects[2].Struct.Leading.Type", Message: "expected a string, found number"},
+		{Path: "Objects[2].Struct.Trailing.Struct.TextStyle.Bold", Message: "expected a boolean, found string"},
+		{Path: "Objects[3].Struct.Icon", Message: `unknown icon "NotAnIcon"`},
+		{Path: "Objects[3].Struct.Text", Message: "expected a string, found number"},
+	}, decodeErr.Problems)
+	assert.True(t, strings.HasPrefix(err.Error(), "5 problems decoding GUI:\n"))
+}
+
+func TestDecodeObjectLenient(t *testing.T) {
+	obj, meta, err := DecodeObjectLenient(strings.NewReader(brokenJSON))
+	require.NotNil(t, err)
+
+	c, ok := obj.(*fyne.Container)
+	require.True(t, ok)
+	assert.Equal(t, "VBox", meta[c]["layout"])
+	require.Equal(t, 3, len(c.Objects))
+	assert.Equal(t, "Hi", c.Objects[0].(*widget.Label).Text)
+	assert.Equal(t, "There", c.Objects[1].(*container.Split).Trailing.(*widget.Label).Text)
+	assert.Nil(t, c.Objects[1].(*container.Split).Leading)
+	assert.Equal(t, "Button", c.Objects[2].(*widget.Button).Text) // the default value is kept
+}
+
+func TestDecodeObject_InvalidDocument(t *testing.T) {
+	_, _, err := DecodeObject(strings.NewReader(`[]`))
+	assert.NotNil(t, err)
+
+	_, _, err = DecodeObject(strings.NewReader(`null`))
+	assert.NotNil(t, err)
+
+	_, _, err = DecodeObject(strings.NewReader(`{"Version": 1}`))
+	assert.NotNil(t, err)
+
+	_, _, err = DecodeObject(strings.NewReader(`{"Version": 1, "Object": {"Type": "*fyne.Container", "Layout": "Spiral"}}`))
+	require.NotNil(t, err)
+	assert.Equal(t, `Layout: unknown layout "Spiral"`, err.Error())
+}
+
+func TestDecodeObject_InvalidValues(t *testing.T) {
+	for in, problem := range map[string]string{
+		`{"Type": "*widget.Label", "Struct": {"propertyLock": {}}}`:                          "Struct.propertyLock: unknown field propertyLock",
+		`{"Type": "*widget.Label", "Struct": {"BaseWidget": null}}`:                          "Struct.BaseWidget: unknown field BaseWidget",
+		`{"Type": "*widget.Entry", "Struct": {"Wrapping": 1000000}}`:                         "Struct.Wrapping: invalid fyne.TextWrap value 1e+06",
+		`{"Type": "*widget.Label", "Struct": {"TextStyle": {"TabWidth": -3}}}`:               "Struct.TextStyle.TabWidth: invalid tab width -3",
+		`{"Type": "*container.Scroll", "Struct": {"Direction": 1.5}}`:                        "Struct.Direction: invalid widget.ScrollDirection value 1.5",
+		`{"Type": "*canvas.Rectangle", "Struct": {"FillColor": {"R": 300}}}`:                 "Struct.FillColor.R: value 300 out of range",
+		`{"Type": "*widget.Accordion", "Struct": {"Items": [{"Title": "A"}]}}`:               "Struct.Items[0].Detail: missing value",
+		`{"Type": "*fyne.Container", "Layout": "GridWrap", "Properties": {"width": "wide"}}`: `Properties.width: invalid value "wide" for the GridWrap layout`,
+	} {
+		_, _, err := DecodeObject(strings.NewReader(`{"Version": 3, "Object": ` + in + `}`))
+		require.NotNil(t, err, in)
+		assert.Equal(t, problem, err.Error(), in)
+	}
+}
+
+func TestDecodeObject_TextStyles(t *testing.T) {
+	obj, _, err := DecodeObject(strings.NewReader(`{"Version": 1, "Object": {"Type": "*widget.RichText", "Struct": {
+  "Segments": [{"Text": "Hi", "Style": {"Alignment": 1, "Inline": true, "SizeName": "headingText",
+    "TextStyle": {"Symbol": true, "Underline": true}}}]}}}`))
+	require.Nil(t, err)
+
+	seg := obj.(*widget.RichText).Segments[0].(*widget.TextSegment)
+	assert.Equal(t, fyne.TextAlignCenter, seg.Style.Alignment)
+	assert.True(t, seg.Style.Inline)
+	assert.Equal(t, fyne.ThemeSizeName("headingText"), seg.Style.SizeName)
+	assert.Equal(t, fyne.TextStyle{Symbol: true, Underline: true}, seg.Style.TextStyle)
+}
+
+func TestDecodeObject_AllClasses(t *testing.T) {
+	guidefs.InitOnce()
+
+	for _, class := range append(WidgetClassList(), GraphicsClassList()...) {
+		t.Run(class, func(t *testing.T) {
+			obj := guidefs.Lookup(class).Create()
+
+			var buf bytes.Buffer
+			require.Nil(t, EncodeObject(obj, nil, &buf))
+			_, _, err := DecodeObject(&buf)
+			assert.Nil(t, err)
+		})
+	}
+}
@@ -0,0 +1,153 @@
+package gui
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/test"
+	"fyne.io/fyne/v2/widget"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func formDesign() (*widget.Form, map[fyne.CanvasObject]map[string]string) {
+	email := widget.NewEntry()
+	remember := widget.NewCheck("Remember me", nil)
+	row := container.NewHBox(remember, widget.NewButton("Forgot", nil))
+	f := &widget.Form{Items: []*widget.FormItem{
+		widget.NewFormItem("Email", email),
+		{Text: "Options", HintText: "Stay signed in", Widget: row},
+	}, SubmitText: "Sign In", OnSubmit: func() {}}
+
+	return f, map[fyne.CanvasObject]map[string]string{
+		f:     {"name": "login", "OnSubmit": "g.signIn"},
+		email: {"name": "email"},
+		row:   {"layout": "HBox", "dir": "horizontal"},
+	}
+}
+
+func TestForm_EncodeDecode(t *testing.T) {
+	f, meta := formDesign()
+
+	var buf bytes.Buffer
+	require.Nil(t, EncodeObject(f, meta, &buf))
+	obj, meta2, err := DecodeObject(&buf)
+	require.Nil(t, err)
+
+	f2 := obj.(*widget.Form)
+	require.Equal(t, 2, len(f2.Items))
+	assert.Equal(t, "Email", f2.Items[0].Text)
+	assert.Equal(t, "email", meta2[f2.Items[0].Widget]["name"])
+	assert.Equal(t, "Options", f2.Items[1].Text)
+	assert.Equal(t, "Stay signed in", f2.Items[1].HintText)
+	row := f2.Items[1].Widget.(*fyne.Container)
+	assert.Equal(t, "HBox", meta2[row]["layout"])
+	assert.Equal(t, "Remember me", row.Objects[0].(*widget.Check).Text)
+	assert.Equal(t, "Sign In", f2.SubmitText)
+	assert.Equal(t, "g.signIn", meta2[f2]["OnSubmit"])
+	assert.Equal(t, "login", meta2[f2]["name"])
+	assert.NotNil(t, f2.OnSubmit)
+	assert.Nil(t, f2.OnCancel)
+}
+
+func TestForm_DecodeMissingWidget(t *testing.T) {
+	_, _, err := DecodeObject(strings.NewReader(`{"Version": 3, "Object": {"Type": "*widget.Form", "Struct": {
+  "Items": [{"Text": "Empty"}, {"Text": "Name", "Widget": {"Type": "*widget.Entry", "Struct": {}}}]}}}`))
+	require.NotNil(t, err)
+	assert.Equal(t, "Struct.Items[0].Widget: missing value", err.Error())
+
+	obj, _, err := DecodeObjectLenient(strings.NewReader(`{"Type":"*widget.Form","Struct":{"Items":[{}]}}`))
+	require.NotNil(t, err)
+	assert.Empty(t, obj.(*widget.Form).Items)
+}
+
+func TestForm_ExportGo(t *testing.T) {
+	f, meta := formDesign()
+
+	var buf bytes.Buffer
+	require.Nil(t, ExportGo(f, meta, "main", &buf))
+	code := strings.Join(strings.Fields(buf.String()), " ")
+	assert.Contains(t, code, "email *widget.Entry")
+	assert.Contains(t, code, `widget.NewFormItem("Email", g.email)`)
+	assert.Contains(t, code, `&widget.FormItem{Text: "Options", HintText: "Stay signed in", Widget: container.NewHBox(`)
+	assert.Contains(t, code, `SubmitText: "Sign In", OnSubmit: g.signIn}`)
+	assert.NotContains(t, code, "CancelText")
+
+	imported, meta2, err := ImportGo(&buf, "")
+	require.Nil(t, err)
+	f2 := imported.(*widget.Form)
+	require.Equal(t, 2, len(f2.Items))
+	assert.Equal(t, "email", meta2[f2.Items[0].Widget]["name"])
+	assert.Equal(t, "Stay signed in", f2.Items[1].HintText)
+	assert.IsType(t, &fyne.Container{}, f2.Items[1].Widget)
+	assert.Equal(t, "Sign In", f2.SubmitText)
+	assert.Equal(t, "g.signIn", meta2[f2]["OnSubmit"])
+}
+
+func TestForm_Editor(t *testing.T) {
+	test.NewTempApp(t)
+	f := CreateNew("*widget.Form").(*widget.Form)
+	w := test.NewTempWindow(t, f)
+	props := map[string]string{}
+	meta := map[fyne.CanvasObject]map[string]string{f: props, f.Items[0].Widget: {"name": "username"}}
+	assert.Nil(t, f.OnSubmit)
+	assert.Nil(t, f.OnCancel)
+
+	var items []*widget.FormItem
+	items = EditorForDesign(f, meta, func(i []*widget.FormItem) {
+		items = i
+	}, nil)
+	itemsFor := func(label string) *widget.FormItem {
+		for _, item := range items {
+			if item.Text == label {
+				return item
+			}
+		}
+		return nil
+	}
+	rowTools := func(label string) (*fyne.Container, *fyne.Container) {
+		row := itemsFor(label).Widget.(*fyne.Container)
+		return row.Objects[0].(*fyne.Container), row.Objects[1].(*fyne.Container)
+	}
+
+	fields, _ := rowTools("Item 1")
+	fields.Objects[0].(*widget.Entry).SetText("Name")
+	fields.Objects[1].(*widget.Entry).SetText("Your full name")
+	assert.Equal(t, "Name", f.Items[0].Text)
+	assert.Equal(t, "Your full name", f.Items[0].HintText)
+
+	old := f.Items[0].Widget
+	fields.Objects[2].(*widget.Select).SetSelected("Check")
+	assert.IsType(t, &widget.Check{}, f.Items[0].Widget)
+	assert.NotContains(t, meta, old)
+
+	_, buttons := rowTools("Item 1")
+	test.Tap(buttons.Objects[1].(*widget.Button)) // move down
+	assert.Equal(t, "Password", f.Items[0].Text)
+	assert.Equal(t, "Name", f.Items[1].Text)
+
+	test.Tap(itemsFor("").Widget.(*widget.Button))
+	require.Equal(t, 3, len(f.Items))
+	assert.Equal(t, "Item 3", f.Items[2].Text)
+
+	_, buttons = rowTools("Item 1")
+	test.Tap(buttons.Objects[2].(*widget.Button)) // delete
+	require.Equal(t, 2, len(f.Items))
+	assert.Equal(t, "Name", f.Items[0].Text)
+
+	itemsFor("Submit Text").Widget.(*widget.Entry).SetText("Save")
+	assert.Equal(t, "Save", f.SubmitText)
+	itemsFor("On Cancel").Widget.(*widget.Entry).SetText("g.close")
+	assert.Equal(t, "g.close", props["OnCancel"])
+	assert.NotNil(t, f.OnCancel)
+	assert.Nil(t, f.OnSubmit)
+	itemsFor("On Cancel").Widget.(*widget.Entry).SetText("")
+	_, ok := props["OnCancel"]
+	assert.False(t, ok)
+	assert.Nil(t, f.OnCancel)
+	assert.Contains(t, test.RenderObjectToMarkup(w.Content()), "Your full name")
+}
@@ -0,0 +1,165 @@
+package gui
+
+import (
+	"bytes"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"fyne.io/fyne/v2"
+)
+
+// designExtension is the file extension of GUI design files.
+const designExtension = ".gui.json"
+
+// FindDesigns returns the paths of the design files within a directory tree, in a stable order.
+// Hidden directories, vendor and testdata are skipped as they do not hold designs of the project.
+func FindDesigns(dir string) ([]string, error) {
+	var designs []string
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			name := d.Name()
+			if path != dir && (strings.HasPrefix(name, ".") || name == "vendor" || name == "testdata") {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		if strings.HasSuffix(d.Name(), designExtension) {
+			designs = append(designs, path)
+		}
+		return nil
+	})
+
+	sort.Strings(designs)
+	return designs, err
+}
+
+// GeneratedFile returns the path of the Go file that is generated from a design, such as "main.gui.go" for "main.gui.json".
+func GeneratedFile(design string) string {
+	return strings.TrimSuffix(design, designExtension) + ".gui.go"
+}
+
+// GeneratedTestFile returns the path of the render test that is generated from a design,
+// such as "main_gui_test.go" for "main.gui.json".
+func GeneratedTestFile(design string) string {
+	return strings.TrimSuffix(design, designExtension) + "_gui_test.go"
+}
+
+// GenerateGo decodes the design file at the path and returns the Go code that `ExportDesignGo` writes for it.
+func GenerateGo(design string) ([]byte, error) {
+	obj, meta, name, err := decodeDesign(design)
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	err = ExportDesignGo(obj, meta, name, &buf)
+	return buf.Bytes(), err
+}
+
+// GenerateGoTest decodes the design file at the path and returns the render test that `ExportGoTest` writes for it.
+func GenerateGoTest(design string) ([]byte, error) {
+	obj, meta, name, err := decodeDesign(design)
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	err = ExportGoTest(obj, meta, name, &buf)
+	return buf.Bytes(), err
+}
+
+// IsGeneratedCurrent returns true if the generated Go file and render test of a design exist and match the code
+// that `GenerateGo` and `GenerateGoTest` return. An error is returned if the design cannot be decoded.
+func IsGeneratedCurrent(design string) (bool, error) {
+	code, err := GenerateGo(design)
+	if err != nil {
+		return false, err
+	}
+	test, err := GenerateGoTest(design)
+	if err != nil {
+		return false, err
+	}
+
+	return fileMatches(GeneratedFile(design), code) && fileMatches(GeneratedTestFile(design), test), nil
+}
+
+func decodeDesign(design string) (fyne.CanvasObject, map[fyne.CanvasObject]map[string]string, string, error) {
+	r, err := os.Open(design)
+	if err != nil {
+		return nil, nil, "", err
+	}
+	obj, meta, err := DecodeObject(r)
+	_ = r.Close()
+	if err != nil {
+		return nil, nil, "", err
+	}
+
+	return obj, meta, strings.TrimSuffix(filepath.Base(design), designExtension), nil
+}
+
+func fileMatches(path string, content []byte) bool {
+	existing, err := os.ReadFile(path)
+	return err == nil && bytes.Equal(content, existing)
+}
+
+// GeneratedClashes returns the declarations of the Go sources that the code generated for the named design
+// would declare again, such as the `makeUI` method of its gui type. Methods are listed as "type.method".
+// Sources that cannot be parsed are skipped.
+func GeneratedClashes(name string, sources ...string) []string {
+	guiName, guiNameUpper := guiTypeName(name)
+	generated := []string{guiName, "new" + guiNameUpper + "GUI", guiName + ".makeUI"}
+
+	var clashes []string
+	for _, src := range sources {
+		file, err := parser.ParseFile(token.NewFileSet(), "", src, 0)
+		if err != nil {
+			continue
+		}
+
+		for _, decl := range sourceDeclarations(file) {
+			if containsString(generated, decl) && !containsString(clashes, decl) {
+				clashes = append(clashes, decl)
+			}
+		}
+	}
+	return clashes
+}
+
+// sourceDeclarations returns the names of the types, functions and methods declared at the top level of a file.
+func sourceDeclarations(file *ast.File) []string {
+	var names []string
+	for _, decl := range file.Decls {
+		switch d := decl.(type) {
+		case *ast.FuncDecl:
+			if d.Recv == nil || len(d.Recv.List) == 0 {
+				names = append(names, d.Name.Name)
+				continue
+			}
+
+			recv := d.Recv.List[0].Type
+			if star, ok := recv.(*ast.StarExpr); ok {
+				recv = star.X
+			}
+			if id, ok := recv.(*ast.Ident); ok {
+				names = append(names, id.Name+"."+d.Name.Name)
+			}
+		case *ast.GenDecl:
+			for _, spec := range d.Specs {
+				if t, ok := spec.(*ast.TypeSpec); ok {
+					names = append(names, t.Name.Name)
+				}
+			}
+		}
+	}
+	return names
+}
@@ -0,0 +1,85 @@
+package gui
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFindDesigns(t *testing.T) {
+	dir := t.TempDir()
+	for _, file := range []string{"main.gui.json", "sub/settings.gui.json", "testdata/old.gui.json",
+		".git/x.gui.json", "notes.json"} {
+		path := filepath.Join(dir, file)
+		require.Nil(t, os.MkdirAll(filepath.Dir(path), 0755))
+		require.Nil(t, os.WriteFile(path, []byte("{}"), 0644))
+	}
+
+	designs, err := FindDesigns(dir)
+	require.Nil(t, err)
+	assert.Equal(t, []string{filepath.Join(dir, "main.gui.json"), filepath.Join(dir, "sub", "settings.gui.json")}, designs)
+	assert.Equal(t, filepath.Join(dir, "sub", "settings.gui.go"), GeneratedFile(designs[1]))
+}
+
+func TestIsGeneratedCurrent(t *testing.T) {
+	design := filepath.Join(t.TempDir(), "settings.gui.json")
+	require.Nil(t, os.WriteFile(design, []byte(documentJSON(labelJSONWith(""))), 0644))
+
+	current, err := IsGeneratedCurrent(design)
+	require.Nil(t, err)
+	assert.False(t, current)
+
+	code, err := GenerateGo(design)
+	require.Nil(t, err)
+	assert.Contains(t, string(code), "type settingsGui struct")
+	require.Nil(t, os.WriteFile(GeneratedFile(design), code, 0644))
+	current, err = IsGeneratedCurrent(design)
+	require.Nil(t, err)
+	assert.False(t, current) // the render test is missing
+
+	test, err := GenerateGoTest(design)
+	require.Nil(t, err)
+	assert.Contains(t, string(test), "func TestSettingsGUI(t *testing.T) {")
+	assert.Equal(t, filepath.Join(filepath.Dir(design), "settings_gui_test.go"), GeneratedTestFile(design))
+	require.Nil(t, os.WriteFile(GeneratedTestFile(design), test, 0644))
+	current, err = IsGeneratedCurrent(design)
+	require.Nil(t, err)
+	assert.True(t, current)
+
+	require.Nil(t, os.WriteFile(GeneratedTestFile(design), append(test, "// edited\n"...), 0644))
+	current, _ = IsGeneratedCurrent(design)
+	assert.False(t, current)
+	require.Nil(t, os.WriteFile(GeneratedTestFile(design), test, 0644))
+
+	require.Nil(t, os.WriteFile(GeneratedFile(design), append(code, "// edited\n"...), 0644))
+	current, _ = IsGeneratedCurrent(design)
+	assert.False(t, current)
+
+	require.Nil(t, os.WriteFile(design, []byte(`{"Version": 1, "Object": {"Type": "*widget.Nope"}}`), 0644))
+	_, err = IsGeneratedCurrent(design)
+	assert.NotNil(t, err)
+}
+
+func TestGeneratedClashes(t *testing.T) {
+	src := `package main
+
+type gui struct{}
+
+func newGUI() *gui {
+	return &gui{}
+}
+
+func (g *gui) makeUI() fyne.CanvasObject {
+	return nil
+}
+
+func main() {}
+`
+	assert.Equal(t, []string{"gui", "newGUI", "gui.makeUI"}, GeneratedClashes("main", src, "not go"))
+	assert.Empty(t, GeneratedClashes("login", src))
+	assert.Equal(t, []string{"loginGui.makeUI"},
+		GeneratedClashes("login", "package main\n\nfunc (l loginGui) makeUI() {}\n"))
+}
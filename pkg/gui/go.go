@@ -4,56 +4,137 @@ import (
 	"fmt"
 	"go/format"
 	"io"
+	"os"
+	"path"
 	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
 	"strings"
 
 	"github.com/fyne-io/defyne/internal/guidefs"
 
 	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
 )
 
 // ExportGo generates a full Go package for the given object and writes it to the provided file handle
 func ExportGo(obj fyne.CanvasObject, meta map[fyne.CanvasObject]map[string]string, name string, w io.Writer) error {
 	guidefs.InitOnce()
 
+	meta = includeMeta(obj, meta)
 	packagesList := packagesRequired(obj, meta)
 	varList := varsRequired(obj, meta)
-	code := exportCode(packagesList, varList, obj, meta, name)
+	code, _, err := exportCode(packagesList, varList, obj, meta, name, "", nil)
+	if err != nil {
+		return err
+	}
 
-	_, err := w.Write([]byte(code))
+	_, err = w.Write([]byte(code))
 	return err
 }
 
+// ExportGoWithHandlers generates a full Go package like `ExportGo`, but the actions of the design call methods
+// of the gui type, such as `OnLoginTapped()`, instead of containing inline code.
+// The methods are listed in a generated handlers interface and should be implemented in a file that is not
+// generated, so that a missing handler is a compile error.
+func ExportGoWithHandlers(obj fyne.CanvasObject, meta map[fyne.CanvasObject]map[string]string, name string, w io.Writer) error {
+	guidefs.InitOnce()
+
+	meta = includeMeta(obj, meta)
+	handlers := handlersRequired(obj, meta, nil)
+	meta = handlerMeta(meta, handlers)
+	packagesList := packagesRequired(obj, meta)
+	varList := varsRequired(obj, meta)
+	code, _, err := exportCode(packagesList, varList, obj, meta, name, "", handlers)
+	if err != nil {
+		return err
+	}
+
+	_, err = w.Write([]byte(code))
+	return err
+}
+
+// ExportDesignGo generates the Go code of a design file, with `ExportGoWithHandlers` if the design `UsesHandlers`
+// and with `ExportGo` otherwise.
+func ExportDesignGo(obj fyne.CanvasObject, meta map[fyne.CanvasObject]map[string]string, name string, w io.Writer) error {
+	if UsesHandlers(obj, meta) {
+		return ExportGoWithHandlers(obj, meta, name, w)
+	}
+
+	return ExportGo(obj, meta, name, w)
+}
+
 // ExportGoPreview generates a preview version of the Go code with a `main()` method for the given object and writes it to the file handle
 func ExportGoPreview(obj fyne.CanvasObject, meta map[fyne.CanvasObject]map[string]string, w io.Writer) error {
 	guidefs.InitOnce()
 
+	return exportGoPreview(obj, meta, nil, w)
+}
+
+func exportGoPreview(obj fyne.CanvasObject, meta map[fyne.CanvasObject]map[string]string, translations []fyne.Resource, w io.Writer) error {
+	meta = includeMeta(obj, meta)
 	packagesList := packagesRequired(obj, meta)
 	packagesList = append(packagesList, "app")
+	if len(translations) > 0 && !containsString(packagesList, "lang") {
+		packagesList = append(packagesList, "lang")
+	}
 	varList := varsRequired(obj, meta)
-	code := exportCode(packagesList, varList, obj, meta, "main")
+	included, packagesList := includePreviews(obj, packagesList)
+	code, _, err := exportCode(packagesList, varList, obj, meta, "main", projectDir(), nil)
+	if err != nil {
+		return err
+	}
+	code += included
 
-	code += `
-func main() {
-	myApp := app.New()
-	myWindow := myApp.NewWindow("Hello")
+	show := `	myWindow := myApp.NewWindow("Hello")
 	gui := newGUI()
 	myWindow.SetContent(gui.makeUI())
-	myWindow.ShowAndRun()
+`
+	if DialogFor(obj, meta) != nil {
+		show = `	myWindow := myApp.NewWindow("Hello")
+	myWindow.Resize(fyne.NewSize(640, 480))
+	showDialog(myWindow)
+`
+	} else if WindowFor(obj, meta) != nil {
+		show = `	myWindow := newGUI().makeWindow(myApp)
+`
+	}
+	code += `
+func main() {
+` + translationsGoString(translations) + `	myApp := app.New()
+` + show + `	myWindow.ShowAndRun()
 }
 `
-	_, err := w.Write([]byte(code))
+	_, err = w.Write([]byte(code))
 
 	return err
 }
 
-func exportCode(pkgs, vars []string, obj fyne.CanvasObject, meta map[fyne.CanvasObject]map[string]string, name string) string {
-	for i := 0; i < len(pkgs); i++ {
-		if pkgs[i] != "fmt" && pkgs[i] != "net/url" && pkgs[i] != "image/color" {
-			pkgs[i] = "fyne.io/fyne/v2/" + pkgs[i]
-		}
+// exportCode generates the Go code for a GUI, project resources are loaded relative to resourceDir if it is set.
+// Any handlers that the object constructors do not refer to are connected after the objects are created.
+// The packages that the code imports are returned with it, including those added for bindings and resources.
+func exportCode(pkgs, vars []string, obj fyne.CanvasObject, meta map[fyne.CanvasObject]map[string]string, name, resourceDir string,
+	handlers []handlerMethod) (string, []string, error) {
+	if resourceDir != "" {
+		resourceDir = strconv.Quote(resourceDir+"/") + " + "
+	}
 
-		pkgs[i] = fmt.Sprintf(`	"%s"`, pkgs[i])
+	binds := bindingsRequired(obj, meta, nil)
+	if err := checkBindings(binds, vars); err != nil {
+		return "", nil, err
+	}
+	if len(binds) > 0 {
+		pkgs = append(pkgs, "data/binding")
+	}
+	for _, b := range binds {
+		vars = append(vars, b.name+" binding."+b.kind)
+	}
+	dlg, win := DialogFor(obj, meta), WindowFor(obj, meta)
+	for _, p := range documentPackages(obj, meta) {
+		if !containsString(pkgs, p) {
+			pkgs = append(pkgs, p)
+		}
 	}
 
 	defs := make(map[string]string)
@@ -61,15 +142,54 @@ func exportCode(pkgs, vars []string, obj fyne.CanvasObject, meta map[fyne.Canvas
 	_, clazz := getTypeOf(obj)
 	main := guidefs.GoString(clazz, obj, meta, defs)
 	setup := ""
-	for k, v := range defs {
-		setup += "g." + k + " = " + v + "\n"
+	for _, k := range defsOrder(obj, meta, defs) {
+		setup += "g." + k + " = " + defs[k] + "\n"
+	}
+	for _, h := range handlers {
+		if refersTo(main+setup, "g."+h.method) {
+			continue
+		}
+
+		objName := meta[h.obj]["name"]
+		if objName == "" {
+			_, class := getTypeOf(h.obj)
+			return "", nil, fmt.Errorf("the %s action of %s can only be connected if the object is named", h.field, class)
+		}
+		setup += "g." + objName + "." + h.field + " = g." + h.method + "\n"
+	}
+	usesResources := strings.Contains(main+setup, "g.loadResource(")
+	if usesResources && !containsString(pkgs, "theme") {
+		pkgs = append(pkgs, "theme")
+	}
+
+	imports := append([]string{}, pkgs...)
+	for i := 0; i < len(pkgs); i++ {
+		if strings.Contains(pkgs[i], " ") { // aliased import from a registered widget
+			pkgs[i] = "\t" + pkgs[i]
+			continue
+		}
+		if pkgs[i] != "fmt" && pkgs[i] != "net/url" && pkgs[i] != "image/color" && !isImportPath(pkgs[i]) {
+			pkgs[i] = "fyne.io/fyne/v2/" + pkgs[i]
+		}
+
+		pkgs[i] = fmt.Sprintf(`	"%s"`, pkgs[i])
 	}
 
-	guiName := "gui"
-	guiNameUpper := ""
-	if name != "main" {
-		guiName = name + "Gui"
-		guiNameUpper = strings.ToUpper(string([]byte{name[0]})) + name[1:]
+	guiName, guiNameUpper := guiTypeName(name)
+	create := "return &" + guiName + "{}"
+	if len(binds) > 0 {
+		create = "g := &" + guiName + "{}\n"
+		for _, b := range binds {
+			create += "g." + b.name + " = binding.New" + b.kind + "()\n"
+			if b.value != "" {
+				create += "_ = g." + b.name + ".Set(" + b.value + ")\n"
+			}
+		}
+		create += "return g"
+	}
+	iface := ""
+	if len(handlers) > 0 {
+		iface = handlersInterface(guiName, handlers)
 	}
 	code := fmt.Sprintf(`// auto-generated
 // Code generated by GUI builder.
@@ -84,9 +204,9 @@ import (
 type %s struct {
 %s
 }
-
+%s
 func new%sGUI() *%s {
-	return &%s{}
+	%s
 }
 
 func (g *%s) makeUI() fyne.CanvasObject {
@@ -97,15 +217,97 @@ func (g *%s) makeUI() fyne.CanvasObject {
 		strings.Join(pkgs, "\n"),
 		guiName,
 		strings.Join(vars, "\n"),
-		guiNameUpper, guiName, guiName, guiName,
+		iface,
+		guiNameUpper, guiName, create, guiName,
 		setup, main)
 
+	if dlg != nil {
+		code += dialogGoString(dlg, name)
+	}
+	if win != nil {
+		code += windowGoString(win, guiName)
+	}
+	if usesResources && resourceDir != "" {
+		code += fmt.Sprintf(`
+func (g *%s) loadResource(path string) fyne.Resource {
+	res, err := fyne.LoadResourceFromPath(%spath)
+	if err != nil {
+		fyne.LogError("Failed to load resource "+path, err)
+		return theme.BrokenImageIcon()
+	}
+	return res
+}
+`, guiName, resourceDir)
+	} else if usesResources {
+		loader, err := bundledLoader(guiName, main+setup)
+		if err != nil {
+			return "", nil, err
+		}
+		code += loader
+	}
+
 	formatted, err := format.Source([]byte(code))
 	if err != nil {
 		fyne.LogError("Failed to format GUI code", err)
-		return code
+		return code, imports, nil
+	}
+	return string(formatted), imports, nil
+}
+
+// resourceCall matches the calls of the generated loadResource helper, the quoted path is the first group.
+var resourceCall = regexp.MustCompile(`g\.loadResource\(("(?:[^"\\]|\\.)*")\)`)
+
+// bundledLoader returns the Go code of a loadResource helper that returns the project resources used by the code.
+// Their content is bundled into the code, like the output of `fyne bundle`, so that the app does not read them from disk.
+func bundledLoader(guiName, code string) (string, error) {
+	var paths []string
+	for _, match := range resourceCall.FindAllStringSubmatch(code, -1) {
+		if p, err := strconv.Unquote(match[1]); err == nil && !containsString(paths, p) {
+			paths = append(paths, p)
+		}
+	}
+	sort.Strings(paths)
+
+	cases := &strings.Builder{}
+	for _, p := range paths {
+		res, err := guidefs.LoadProjectResource(p)
+		if err != nil {
+			return "", fmt.Errorf("failed to bundle resource %q: %w", p, err)
+		}
+		cases.WriteString(fmt.Sprintf("case %q:\nreturn &fyne.StaticResource{StaticName: %q, StaticContent: []byte(%q)}\n",
+			p, path.Base(p), res.Content()))
 	}
-	return string(formatted)
+
+	return fmt.Sprintf(`
+func (g *%s) loadResource(path string) fyne.Resource {
+	switch path {
+	%s}
+
+	fyne.LogError("Unknown resource "+path, nil)
+	return theme.BrokenImageIcon()
+}
+`, guiName, cases.String()), nil
+}
+
+// guiTypeName returns the name of the gui type generated for a design, and the name used in its constructor.
+// The design in the main file uses `gui` and `newGUI`, others are prefixed with their name.
+func guiTypeName(name string) (string, string) {
+	if name == "main" {
+		return "gui", ""
+	}
+	return name + "Gui", strings.ToUpper(string([]byte{name[0]})) + name[1:]
+}
+
+// documentPackages returns the packages needed by the dialog or window that a design is shown in.
+func documentPackages(obj fyne.CanvasObject, meta map[fyne.CanvasObject]map[string]string) []string {
+	var pkgs []string
+	if DialogFor(obj, meta) != nil {
+		pkgs = append(pkgs, "dialog")
+	}
+	if win := WindowFor(obj, meta); win != nil {
+		pkgs = append(pkgs, windowPackages(win)...)
+	}
+	return pkgs
 }
 
 func packagesRequired(obj fyne.CanvasObject, meta map[fyne.CanvasObject]map[string]string) []string {
@@ -117,15 +319,18 @@ func packagesRequired(obj fyne.CanvasObject, meta map[fyne.CanvasObject]map[stri
 		if ok && layout == "Form" {
 			ret = append(ret, "layout")
 		}
+		if custom, ok := guidefs.CustomLayouts[layout]; ok && custom.Packages != nil {
+			ret = append(ret, custom.Packages(c)...)
+		}
 	} else {
 		class := reflect.TypeOf(obj).String()
 		info := guidefs.Lookup(class)
 
 		if info != nil && info.IsContainer() {
-			ret = packagesRequiredForWidget(obj)
+			ret = packagesRequiredForWidget(obj, meta[obj])
 			objs = info.Children(obj)
 		} else {
-			return packagesRequiredForWidget(obj)
+			return packagesRequiredForWidget(obj, meta[obj])
 		}
 	}
 
@@ -146,17 +351,146 @@ func packagesRequired(obj fyne.CanvasObject, meta map[fyne.CanvasObject]map[stri
 	return ret
 }
 
-func packagesRequiredForWidget(w fyne.CanvasObject) []string {
+func packagesRequiredForWidget(w fyne.CanvasObject, props map[string]string) []string {
 	name := reflect.TypeOf(w).String()
-	if pkgs := guidefs.Lookup(name).Packages; pkgs != nil {
-		return pkgs(w)
+	pkgs := []string{}
+	if info := guidefs.Lookup(name); info != nil && info.Packages != nil {
+		pkgs = packagesForThemeColors(w, props, info.Packages(w))
+	} else if _, ok := w.(fyne.Widget); ok {
+		pkgs = []string{"widget"}
 	}
 
-	if _, ok := w.(fyne.Widget); ok {
-		return []string{"widget"}
+	if len(guidefs.Translations(name, props)) > 0 {
+		pkgs = append(pkgs, "lang")
 	}
+	return pkgs
+}
 
-	return []string{}
+// packagesForThemeColors adds the theme package if any colors are looked up from the theme,
+// and removes the color package if it is no longer used by the remaining color fields.
+func packagesForThemeColors(obj fyne.CanvasObject, props map[string]string, pkgs []string) []string {
+	colors := guidefs.ThemeColors(obj, props)
+	if len(colors) == 0 {
+		return pkgs
+	}
+
+	literal := false
+	val := reflect.ValueOf(obj).Elem()
+	for _, field := range guidefs.ColorFields(obj) {
+		if _, ok := colors[field]; !ok && !val.FieldByName(field).IsNil() {
+			literal = true
+		}
+	}
+
+	ret := []string{"theme"}
+	for _, p := range pkgs {
+		if p == "theme" || (p == "image/color" && !literal) {
+			continue
+		}
+		ret = append(ret, p)
+	}
+	return ret
+}
+
+type bindingVar struct {
+	name, kind, value string
+}
+
+// bindingsRequired returns the data bindings used in the object tree, in the order they are first used.
+// A name that is bound to different types of value is returned once for each type, see `checkBindings`.
+func bindingsRequired(obj fyne.CanvasObject, props map[fyne.CanvasObject]map[string]string, ret []bindingVar) []bindingVar {
+	if obj == nil {
+		return ret
+	}
+
+	class := reflect.TypeOf(obj).String()
+	if name := props[obj]["binding"]; name != "" {
+		if info, ok := guidefs.Bindings[class]; ok {
+			found := false
+			for _, b := range ret {
+				if b.name == name && b.kind == info.Type {
+					found = true
+					break
+				}
+			}
+			if !found {
+				ret = append(ret, bindingVar{name: name, kind: info.Type, value: info.Value(obj)})
+			}
+		}
+	}
+
+	if c, ok := obj.(*fyne.Container); ok {
+		for _, w := range c.Objects {
+			ret = bindingsRequired(w, props, ret)
+		}
+	} else if info := guidefs.Lookup(class); info != nil && info.IsContainer() {
+		for _, w := range info.Children(obj) {
+			ret = bindingsRequired(w, props, ret)
+		}
+	}
+	return ret
+}
+
+// defsOrder returns the names of the definitions in tree order, children are defined before their parents.
+// Any definitions that are not found in the tree follow in alphabetical order.
+func defsOrder(obj fyne.CanvasObject, meta map[fyne.CanvasObject]map[string]string, defs map[string]string) []string {
+	var ret []string
+	var walk func(fyne.CanvasObject)
+	walk = func(o fyne.CanvasObject) {
+		if o == nil {
+			return
+		}
+
+		switch c := o.(type) {
+		case *fyne.Container:
+			for _, child := range c.Objects {
+				walk(child)
+			}
+		case *container.AppTabs:
+			for _, item := range c.Items {
+				walk(item.Content)
+			}
+		default:
+			if info := guidefs.Lookup(reflect.TypeOf(o).String()); info != nil && info.IsContainer() {
+				for _, child := range info.Children(o) {
+					walk(child)
+				}
+			}
+		}
+
+		name := meta[o]["name"]
+		if _, ok := defs[name]; ok && !containsString(ret, name) {
+			ret = append(ret, name)
+		}
+	}
+	walk(obj)
+
+	var rest []string
+	for name := range defs {
+		if !containsString(ret, name) {
+			rest = append(rest, name)
+		}
+	}
+	sort.Strings(rest)
+	return append(ret, rest...)
+}
+
+// checkBindings returns an error if the gui type could not declare the bindings, because a name is bound to
+// different types of value or is also the name of an object.
+func checkBindings(binds []bindingVar, vars []string) error {
+	for i, b := range binds {
+		for _, other := range binds[:i] {
+			if other.name == b.name {
+				return fmt.Errorf("binding %s is used as both binding.%s and binding.%s", b.name, other.kind, b.kind)
+			}
+		}
+		for _, v := range vars {
+			if strings.Fields(v)[0] == b.name {
+				return fmt.Errorf("binding %s has the same name as an object", b.name)
+			}
+		}
+	}
+	return nil
 }
 
 func varsRequired(obj fyne.CanvasObject, props map[fyne.CanvasObject]map[string]string) []string {
@@ -182,7 +516,11 @@ func varsRequired(obj fyne.CanvasObject, props map[fyne.CanvasObject]map[string]
 		}
 	}
 
-	if w, ok := obj.(fyne.Widget); ok {
+	if inc, ok := obj.(*guidefs.Include); ok {
+		if typeName, _ := guidefs.IncludeGoNames(inc.Src); name != "" && typeName != "" {
+			ret = append(ret, name+" *"+typeName)
+		}
+	} else if w, ok := obj.(fyne.Widget); ok {
 		if name != "" {
 			_, class := getTypeOf(w)
 			ret = append(ret, name+" "+class)
@@ -190,3 +528,28 @@ func varsRequired(obj fyne.CanvasObject, props map[fyne.CanvasObject]map[string]
 	}
 	return ret
 }
+
+// projectDir returns the absolute path of the directory that project resources are loaded from.
+func projectDir() string {
+	if guidefs.ProjectRoot != nil {
+		return guidefs.ProjectRoot.Path()
+	}
+
+	dir, _ := os.Getwd()
+	return dir
+}
+
+func containsString(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
+
+// isImportPath returns true if the package is a full import path, such as "fyne.io/x/fyne/widget",
+// rather than the name of a Fyne package.
+func isImportPath(pkg string) bool {
+	return strings.Contains(strings.Split(pkg, "/")[0], ".")
+}
@@ -0,0 +1,96 @@
+package gui
+
+import (
+	"bytes"
+	"flag"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/widget"
+
+	"github.com/fyne-io/defyne/internal/guidefs"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var update = flag.Bool("update", false, "update the golden files in testdata")
+
+func TestExportGo_Golden(t *testing.T) {
+	guidefs.InitOnce()
+
+	var classes []string
+	classes = append(classes, WidgetClassList()...)
+	classes = append(classes, ContainerClassList()...)
+	classes = append(classes, CollectionClassList()...)
+	classes = append(classes, GraphicsClassList()...)
+
+	for _, class := range classes {
+		t.Run(class, func(t *testing.T) {
+			obj := CreateNew(class)
+			require.NotNil(t, obj)
+
+			var meta map[fyne.CanvasObject]map[string]string
+			if c, ok := obj.(*fyne.Container); ok {
+				meta = map[fyne.CanvasObject]map[string]string{c: {"layout": "VBox", "dir": "vertical"}}
+			}
+			assertGoldenGo(t, goldenName(class), obj, meta)
+		})
+	}
+}
+
+func TestExportGo_Order(t *testing.T) {
+	title := widget.NewLabel("Title")
+	name := widget.NewEntry()
+	save := widget.NewButton("Save", nil)
+	form := container.NewVBox(name, save)
+	split := container.NewHSplit(title, form)
+
+	meta := map[fyne.CanvasObject]map[string]string{
+		title: {"name": "title"},
+		name:  {"name": "name"},
+		save:  {"name": "save"},
+		form:  {"name": "form", "layout": "VBox", "dir": "vertical"},
+		split: {"name": "split"},
+	}
+	assertGoldenGo(t, "order", split, meta)
+
+	var first bytes.Buffer
+	require.Nil(t, ExportGo(split, meta, "main", &first))
+	for i := 0; i < 10; i++ {
+		var buf bytes.Buffer
+		require.Nil(t, ExportGo(split, meta, "main", &buf))
+		assert.Equal(t, first.String(), buf.String())
+	}
+
+	code := first.String()
+	assert.Less(t, strings.Index(code, "g.name = "), strings.Index(code, "g.form = "))
+	assert.Less(t, strings.Index(code, "g.form = "), strings.Index(code, "g.split = "))
+}
+
+func assertGoldenGo(t *testing.T, name string, obj fyne.CanvasObject, meta map[fyne.CanvasObject]map[string]string) {
+	var buf bytes.Buffer
+	require.Nil(t, ExportGo(obj, meta, "main", &buf))
+	_, err := parser.ParseFile(token.NewFileSet(), name+".go", buf.Bytes(), 0)
+	require.Nil(t, err)
+
+	path := filepath.Join("testdata", "go", name+".go.golden")
+	if *update {
+		require.Nil(t, os.MkdirAll(filepath.Dir(path), 0o755))
+		require.Nil(t, os.WriteFile(path, buf.Bytes(), 0o644))
+	}
+
+	golden, err := os.ReadFile(path)
+	require.Nil(t, err, "missing golden file, run the tests with -update to create it")
+	assert.Equal(t, string(golden), buf.String())
+}
+
+// goldenName returns the file name used for a class, for example "widget.Button" for "*widget.Button".
+func goldenName(class string) string {
+	return strings.TrimPrefix(class, "*")
+}
@@ -0,0 +1,174 @@
+package gui
+
+import (
+	"go/token"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"fyne.io/fyne/v2"
+
+	"github.com/fyne-io/defyne/internal/guidefs"
+)
+
+// handlersKey marks a design whose Go code calls handler methods for its actions, in the metadata of the root object.
+const handlersKey = "handlers"
+
+// UsesHandlers returns true if the Go code of the design is generated by `ExportGoWithHandlers` instead of `ExportGo`.
+func UsesHandlers(obj fyne.CanvasObject, meta map[fyne.CanvasObject]map[string]string) bool {
+	return meta[obj][handlersKey] == "true"
+}
+
+// SetUsesHandlers sets whether the Go code of the design is generated by `ExportGoWithHandlers` instead of `ExportGo`.
+// The setting is stored in the metadata of the root object.
+func SetUsesHandlers(obj fyne.CanvasObject, meta map[fyne.CanvasObject]map[string]string, handlers bool) {
+	props, ok := meta[obj]
+	if !ok {
+		props = make(map[string]string)
+		meta[obj] = props
+	}
+
+	if handlers {
+		props[handlersKey] = "true"
+	} else {
+		delete(props, handlersKey)
+	}
+}
+
+// handlerDefaults lists the actions that are exported as handlers even if they are not set,
+// as they would otherwise be generated as empty functions.
+var handlerDefaults = map[string][]string{
+	"*widget.Button": {"OnTapped"},
+}
+
+// handlerMethod is an action of an object that is handled by a method of the gui type.
+type handlerMethod struct {
+	obj                   fyne.CanvasObject
+	field, method, params string
+}
+
+// handlersRequired returns the handler methods for the actions in the object tree, in tree order.
+// An action that already calls a method, like "g.onSave", keeps that name, otherwise a name such as
+// `OnLoginTapped` is created from the object name and the action.
+func handlersRequired(obj fyne.CanvasObject, meta map[fyne.CanvasObject]map[string]string, ret []handlerMethod) []handlerMethod {
+	if obj == nil {
+		return ret
+	}
+
+	class := reflect.TypeOf(obj).String()
+	props := meta[obj]
+	fields := append([]string{}, handlerDefaults[class]...)
+	for _, k := range sortedActions(props) {
+		if !containsString(fields, k) {
+			fields = append(fields, k)
+		}
+	}
+
+	for _, field := range fields {
+		f := reflect.ValueOf(obj).Elem().FieldByName(field)
+		if !f.IsValid() || f.Kind() != reflect.Func {
+			continue
+		}
+
+		method := strings.TrimPrefix(props[field], "g.")
+		if method == props[field] || !token.IsIdentifier(method) {
+			method = uniqueMethod(handlerName(obj, props["name"], field), ret)
+		}
+		ret = append(ret, handlerMethod{obj: obj, field: field, method: method,
+			params: strings.TrimPrefix(f.Type().String(), "func")})
+	}
+
+	if c, ok := obj.(*fyne.Container); ok {
+		for _, w := range c.Objects {
+			ret = handlersRequired(w, meta, ret)
+		}
+	} else if info := guidefs.Lookup(class); info != nil && info.IsContainer() {
+		for _, w := range info.Children(obj) {
+			ret = handlersRequired(w, meta, ret)
+		}
+	}
+	return ret
+}
+
+// handlerMeta returns a copy of the metadata with every action set to call its handler method.
+func handlerMeta(meta map[fyne.CanvasObject]map[string]string, handlers []handlerMethod) map[fyne.CanvasObject]map[string]string {
+	ret := make(map[fyne.CanvasObject]map[string]string, len(meta))
+	for obj, props := range meta {
+		copied := make(map[string]string, len(props))
+		for k, v := range props {
+			copied[k] = v
+		}
+		ret[obj] = copied
+	}
+
+	for _, h := range handlers {
+		props, ok := ret[h.obj]
+		if !ok {
+			props = make(map[string]string)
+			ret[h.obj] = props
+		}
+		props[h.field] = "g." + h.method
+	}
+	return ret
+}
+
+// handlersInterface returns the Go code for an interface of the handler methods,
+// and a check that the gui type implements it.
+func handlersInterface(guiName string, handlers []handlerMethod) string {
+	var methods []string
+	for _, h := range handlers {
+		method := h.method + h.params
+		if !containsString(methods, method) {
+			methods = append(methods, method)
+		}
+	}
+
+	iface := guiName + "Handlers"
+	return "\n// " + iface + " lists the event handlers of " + guiName + ", they should be implemented in a file that is not generated.\n" +
+		"type " + iface + " interface {\n" + strings.Join(methods, "\n") + "\n}\n\n" +
+		"var _ " + iface + " = (*" + guiName + ")(nil)\n"
+}
+
+// handlerName returns a method name like `OnLoginTapped` for an action, using the type name if the object has no name.
+func handlerName(obj fyne.CanvasObject, name, field string) string {
+	if name == "" {
+		name = NameOf(obj)
+	}
+
+	return "On" + strings.ToUpper(name[0:1]) + name[1:] + strings.TrimPrefix(field, "On")
+}
+
+func uniqueMethod(method string, handlers []handlerMethod) string {
+	unique := method
+	for i := 2; ; i++ {
+		used := false
+		for _, h := range handlers {
+			if h.method == unique {
+				used = true
+				break
+			}
+		}
+		if !used {
+			return unique
+		}
+		unique = method + strconv.Itoa(i)
+	}
+}
+
+// refersTo returns true if the code contains the identifier, such as "g.OnSaveTapped".
+func refersTo(code, ident string) bool {
+	return regexp.MustCompile(regexp.QuoteMeta(ident) + `\b`).MatchString(code)
+}
+
+func sortedActions(props map[string]string) []string {
+	var keys []string
+	for k := range props {
+		if len(k) > 2 && k[0:2] == "On" {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,107 @@
+package gui
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/widget"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExportGoWithHandlers(t *testing.T) {
+	login := widget.NewButton("Login", nil)
+	help := widget.NewButton("Help", nil)
+	remember := widget.NewCheck("Remember me", nil)
+	c := container.NewVBox(login, help, remember)
+	meta := map[fyne.CanvasObject]map[string]string{
+		c:        {"layout": "VBox", "dir": "vertical"},
+		login:    {"name": "login"},
+		help:     {"OnTapped": "g.showHelp"},
+		remember: {"name": "remember", "OnChanged": `func(bool) { println("changed") }`},
+	}
+
+	var buf bytes.Buffer
+	require.Nil(t, ExportGoWithHandlers(c, meta, "main", &buf))
+	code := buf.String()
+	assert.Contains(t, code, "type guiHandlers interface {\n\tOnLoginTapped()\n\tshowHelp()\n\tOnRememberChanged(bool)\n}\n")
+	assert.Contains(t, code, "var _ guiHandlers = (*gui)(nil)\n")
+	assert.Contains(t, code, `g.login = widget.NewButton("Login", g.OnLoginTapped)`)
+	assert.Contains(t, code, `widget.NewButton("Help", g.showHelp)`)
+	assert.Contains(t, code, "g.remember.OnChanged = g.OnRememberChanged\n")
+	assert.NotContains(t, code, "func() {}")
+	assert.Equal(t, `func(bool) { println("changed") }`, meta[remember]["OnChanged"]) // not modified
+
+	imported, meta2, err := ImportGo(&buf, "")
+	require.Nil(t, err)
+	objs := imported.(*fyne.Container).Objects
+	assert.Equal(t, "g.OnLoginTapped", meta2[objs[0]]["OnTapped"])
+	assert.Equal(t, "g.showHelp", meta2[objs[1]]["OnTapped"])
+	assert.Equal(t, "g.OnRememberChanged", meta2[objs[2]]["OnChanged"])
+
+	buf.Reset()
+	require.Nil(t, ExportGoWithHandlers(imported, meta2, "main", &buf))
+	assert.Equal(t, code, buf.String())
+}
+
+func TestExportGoWithHandlers_Unnamed(t *testing.T) {
+	b1 := widget.NewButton("One", nil)
+	b2 := widget.NewButton("Two", nil)
+	c := container.NewHBox(b1, b2)
+	meta := map[fyne.CanvasObject]map[string]string{c: {"layout": "HBox", "dir": "horizontal"}}
+
+	var buf bytes.Buffer
+	require.Nil(t, ExportGoWithHandlers(c, meta, "main", &buf))
+	assert.Contains(t, buf.String(), "\tOnButtonTapped()\n\tOnButtonTapped2()\n")
+
+	check := widget.NewCheck("Agree", nil)
+	meta = map[fyne.CanvasObject]map[string]string{check: {"OnChanged": "g.agreed"}}
+	err := ExportGoWithHandlers(check, meta, "main", &buf)
+	require.NotNil(t, err)
+	assert.Equal(t, "the OnChanged action of *widget.Check can only be connected if the object is named", err.Error())
+}
+
+func TestUsesHandlers(t *testing.T) {
+	login := widget.NewButton("Login", nil)
+	c := container.NewVBox(login)
+	meta := map[fyne.CanvasObject]map[string]string{
+		c:     {"layout": "VBox", "dir": "vertical"},
+		login: {"name": "login"},
+	}
+	assert.False(t, UsesHandlers(c, meta))
+	SetUsesHandlers(c, meta, true)
+	assert.True(t, UsesHandlers(c, meta))
+
+	var buf bytes.Buffer
+	require.Nil(t, EncodeObject(c, meta, &buf))
+	assert.Contains(t, buf.String(), `"Handlers": true`)
+	assert.NotContains(t, buf.String(), `"handlers"`)
+	design := filepath.Join(t.TempDir(), "login.gui.json")
+	require.Nil(t, os.WriteFile(design, buf.Bytes(), 0644))
+
+	code, err := GenerateGo(design)
+	require.Nil(t, err)
+	assert.Contains(t, string(code), "type loginGuiHandlers interface {\n\tOnLoginTapped()\n}\n")
+	require.Nil(t, os.WriteFile(GeneratedFile(design), code, 0644))
+	test, err := GenerateGoTest(design)
+	require.Nil(t, err)
+	require.Nil(t, os.WriteFile(GeneratedTestFile(design), test, 0644))
+	current, err := IsGeneratedCurrent(design)
+	require.Nil(t, err)
+	assert.True(t, current)
+
+	SetUsesHandlers(c, meta, false)
+	assert.False(t, UsesHandlers(c, meta))
+	buf.Reset()
+	require.Nil(t, EncodeObject(c, meta, &buf))
+	assert.NotContains(t, buf.String(), "Handlers")
+	require.Nil(t, os.WriteFile(design, buf.Bytes(), 0644))
+	current, err = IsGeneratedCurrent(design)
+	require.Nil(t, err)
+	assert.False(t, current)
+}
@@ -0,0 +1,1037 @@
+package gui
+
+import (
+	"errors"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"image/color"
+	"io"
+	"net/url"
+	"path"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/fyne-io/defyne/internal/guidefs"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/canvas"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/layout"
+	"fyne.io/fyne/v2/widget"
+)
+
+var canvasObjectType = reflect.TypeOf((*fyne.CanvasObject)(nil)).Elem()
+
+// importFuncs are the constructors that can be called while importing Go code.
+var importFuncs = map[string]interface{}{
+	"canvas.NewRectangle": canvas.NewRectangle,
+
+	"container.NewAppTabs":         container.NewAppTabs,
+	"container.NewHScroll":         container.NewHScroll,
+	"container.NewHSplit":          container.NewHSplit,
+	"container.NewScroll":          container.NewScroll,
+	"container.NewTabItem":         container.NewTabItem,
+	"container.NewTabItemWithIcon": container.NewTabItemWithIcon,
+	"container.NewVScroll":         container.NewVScroll,
+	"container.NewVSplit":          container.NewVSplit,
+
+	"fyne.NewMenu":     fyne.NewMenu,
+	"fyne.NewMenuItem": fyne.NewMenuItem,
+	"fyne.NewPos":      fyne.NewPos,
+	"fyne.NewSize":     fyne.NewSize,
+
+	"layout.NewSpacer": layout.NewSpacer,
+
+	"widget.NewAccordion":            widget.NewAccordion,
+	"widget.NewAccordionItem":        widget.NewAccordionItem,
+	"widget.NewButton":               widget.NewButton,
+	"widget.NewButtonWithIcon":       widget.NewButtonWithIcon,
+	"widget.NewCard":                 widget.NewCard,
+	"widget.NewCheck":                widget.NewCheck,
+	"widget.NewDateEntry":            widget.NewDateEntry,
+	"widget.NewEntry":                widget.NewEntry,
+	"widget.NewForm":                 widget.NewForm,
+	"widget.NewFormItem":             widget.NewFormItem,
+	"widget.NewHyperlink":            widget.NewHyperlink,
+	"widget.NewIcon":                 widget.NewIcon,
+	"widget.NewLabel":                widget.NewLabel,
+	"widget.NewLabelWithStyle":       widget.NewLabelWithStyle,
+	"widget.NewMenu":                 widget.NewMenu,
+	"widget.NewMultiLineEntry":       widget.NewMultiLineEntry,
+	"widget.NewPasswordEntry":        widget.NewPasswordEntry,
+	"widget.NewProgressBar":          widget.NewProgressBar,
+	"widget.NewRadioGroup":           widget.NewRadioGroup,
+	"widget.NewRichTextFromMarkdown": widget.NewRichTextFromMarkdown,
+	"widget.NewSelect":               widget.NewSelect,
+	"widget.NewSeparator":            widget.NewSeparator,
+	"widget.NewSlider":               widget.NewSlider,
+	"widget.NewTextGrid":             widget.NewTextGrid,
+	"widget.NewTextGridFromString":   widget.NewTextGridFromString,
+	"widget.NewToolbar":              widget.NewToolbar,
+	"widget.NewToolbarAction":        widget.NewToolbarAction,
+	"widget.NewToolbarSeparator":     widget.NewToolbarSeparator,
+	"widget.NewToolbarSpacer":        widget.NewToolbarSpacer,
+}
+
+// importActions lists the constructors with a callback parameter that is stored as an action.
+var importActions = map[string]string{
+	"widget.NewButton":         "OnTapped",
+	"widget.NewButtonWithIcon": "OnTapped",
+}
+
+// importBindings are the data bound constructors, the last parameter names the binding and
+// the others are passed to the matching function here to create the widget.
+var importBindings = map[string]interface{}{
+	"widget.NewCheckWithData": func(label string) *widget.Check {
+		return widget.NewCheck(label, nil)
+	},
+	"widget.NewEntryWithData": widget.NewEntry,
+	"widget.NewLabelWithData": func() *widget.Label {
+		return widget.NewLabel("")
+	},
+	"widget.NewProgressBarWithData": widget.NewProgressBar,
+	"widget.NewSliderWithData":      widget.NewSlider,
+}
+
+// importDefaults are constructors whose callbacks cannot be represented, so the default instance is used.
+var importDefaults = map[string]string{
+	"widget.NewList":            "*widget.List",
+	"widget.NewTable":           "*widget.Table",
+	"widget.NewTree":            "*widget.Tree",
+	"widget.NewTreeWithStrings": "*widget.Tree",
+}
+
+// importLayouts maps container and layout constructors to the names in the layout list.
+var importLayouts = map[string]string{
+	"container.NewBorder":          "Border",
+	"container.NewCenter":          "Center",
+	"container.NewGridWithColumns": "Grid",
+	"container.NewGridWithRows":    "Grid",
+	"container.NewGridWrap":        "GridWrap",
+	"container.NewHBox":            "HBox",
+	"container.NewMax":             "Stack",
+	"container.NewPadded":          "Padded",
+	"container.NewStack":           "Stack",
+	"container.NewVBox":            "VBox",
+
+	"layout.NewBorderLayout":          "Border",
+	"layout.NewCenterLayout":          "Center",
+	"layout.NewFormLayout":            "Form",
+	"layout.NewGridLayout":            "Grid",
+	"layout.NewGridLayoutWithColumns": "Grid",
+	"layout.NewGridLayoutWithRows":    "Grid",
+	"layout.NewGridWrapLayout":        "GridWrap",
+	"layout.NewHBoxLayout":            "HBox",
+	"layout.NewMaxLayout":             "Stack",
+	"layout.NewPaddedLayout":          "Padded",
+	"layout.NewStackLayout":           "Stack",
+	"layout.NewVBoxLayout":            "VBox",
+}
+
+var importConsts = map[string]interface{}{
+	"container.ScrollBoth":           container.ScrollBoth,
+	"container.ScrollHorizontalOnly": container.ScrollHorizontalOnly,
+	"container.ScrollNone":           container.ScrollNone,
+	"container.ScrollVerticalOnly":   container.ScrollVerticalOnly,
+
+	"fyne.TextAlignCenter":   fyne.TextAlignCenter,
+	"fyne.TextAlignLeading":  fyne.TextAlignLeading,
+	"fyne.TextAlignTrailing": fyne.TextAlignTrailing,
+	"fyne.TextTruncateClip":  fyne.TextTruncateClip,
+	"fyne.TextTruncateOff":   fyne.TextTruncateOff,
+	"fyne.TextWrapBreak":     fyne.TextWrapBreak,
+	"fyne.TextWrapOff":       fyne.TextWrapOff,
+	"fyne.TextWrapWord":      fyne.TextWrapWord,
+
+	"widget.ButtonAlignCenter":      widget.ButtonAlignCenter,
+	"widget.ButtonAlignLeading":     widget.ButtonAlignLeading,
+	"widget.ButtonAlignTrailing":    widget.ButtonAlignTrailing,
+	"widget.ButtonIconLeadingText":  widget.ButtonIconLeadingText,
+	"widget.ButtonIconTrailingText": widget.ButtonIconTrailingText,
+	"widget.DangerImportance":       widget.DangerImportance,
+	"widget.HighImportance":         widget.HighImportance,
+	"widget.Horizontal":             widget.Horizontal,
+	"widget.LowImportance":          widget.LowImportance,
+	"widget.MediumImportance":       widget.MediumImportance,
+	"widget.SuccessImportance":      widget.SuccessImportance,
+	"widget.Vertical":               widget.Vertical,
+	"widget.WarningImportance":      widget.WarningImportance,
+}
+
+var importTypes = map[string]reflect.Type{
+	"color.Gray":     reflect.TypeOf(color.Gray{}),
+	"color.Gray16":   reflect.TypeOf(color.Gray16{}),
+	"color.NRGBA":    reflect.TypeOf(color.NRGBA{}),
+	"color.RGBA":     reflect.TypeOf(color.RGBA{}),
+	"fyne.Position":  reflect.TypeOf(fyne.Position{}),
+	"fyne.Size":      reflect.TypeOf(fyne.Size{}),
+	"fyne.TextStyle": reflect.TypeOf(fyne.TextStyle{}),
+	"url.URL":        reflect.TypeOf(url.URL{}),
+}
+
+type goVar struct {
+	expr      ast.Expr
+	ops       []ast.Stmt
+	obj       fyne.CanvasObject
+	resolving bool
+}
+
+// goText is a string that was looked up with the lang package, it is stored as a translation of the object that uses it.
+type goText struct {
+	key, text string
+}
+
+type goImporter struct {
+	fset  *token.FileSet
+	src   []byte
+	pkgs  map[string]string
+	vars  map[string]*goVar
+	meta  map[fyne.CanvasObject]map[string]string
+	texts []goText
+}
+
+// ImportGo parses Go source code and returns the tree of `CanvasObject` elements built by the named function,
+// along with the metadata map in the same form returned by `DecodeObject`.
+// If funcName is empty the `makeUI` function will be imported.
+func ImportGo(r io.Reader, funcName string) (fyne.CanvasObject, map[fyne.CanvasObject]map[string]string, error) {
+	guidefs.InitOnce()
+
+	src, err := io.ReadAll(r)
+	if err != nil {
+		return nil, nil, err
+	}
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "", src, 0)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if funcName == "" {
+		funcName = "makeUI"
+	}
+	var fn *ast.FuncDecl
+	for _, d := range file.Decls {
+		if f, ok := d.(*ast.FuncDecl); ok && f.Name.Name == funcName && f.Body != nil {
+			fn = f
+			break
+		}
+	}
+	if fn == nil {
+		return nil, nil, errors.New("function " + funcName + " not found")
+	}
+
+	imp := &goImporter{fset: fset, src: src, pkgs: importedPackages(file),
+		vars: make(map[string]*goVar), meta: make(map[fyne.CanvasObject]map[string]string)}
+	obj, err := imp.importBody(fn.Body)
+	if err != nil {
+		return nil, nil, err
+	}
+	return obj, imp.meta, nil
+}
+
+// importedPackages maps the names used in a file to the Fyne package names that we understand.
+func importedPackages(file *ast.File) map[string]string {
+	pkgs := make(map[string]string)
+	for _, i := range file.Imports {
+		p, _ := strconv.Unquote(i.Path.Value)
+		name := path.Base(p)
+		if p == "fyne.io/fyne/v2" {
+			name = "fyne"
+		}
+
+		alias := name
+		if i.Name != nil {
+			alias = i.Name.Name
+		}
+		pkgs[alias] = name
+	}
+	return pkgs
+}
+
+func (i *goImporter) importBody(body *ast.BlockStmt) (fyne.CanvasObject, error) {
+	for _, s := range body.List {
+		switch stmt := s.(type) {
+		case *ast.AssignStmt:
+			if len(stmt.Lhs) != 1 || len(stmt.Rhs) != 1 {
+				continue
+			}
+
+			if sel, ok := stmt.Lhs[0].(*ast.SelectorExpr); ok {
+				if v := i.lookupVar(sel.X); v != nil {
+					v.ops = append(v.ops, stmt)
+					continue
+				}
+			}
+			if name := assignedName(stmt.Lhs[0]); name != "" {
+				i.vars[name] = &goVar{expr: stmt.Rhs[0]}
+			}
+		case *ast.ExprStmt:
+			call, ok := stmt.X.(*ast.CallExpr)
+			if !ok {
+				continue
+			}
+			if sel, ok := call.Fun.(*ast.SelectorExpr); ok {
+				if v := i.lookupVar(sel.X); v != nil {
+					v.ops = append(v.ops, stmt)
+				}
+			}
+		case *ast.ReturnStmt:
+			if len(stmt.Results) != 1 {
+				return nil, i.errorf(stmt, "expected a single return value")
+			}
+			return i.object(stmt.Results[0])
+		}
+	}
+
+	return nil, errors.New("no return statement found")
+}
+
+func assignedName(e ast.Expr) string {
+	switch v := e.(type) {
+	case *ast.Ident:
+		if v.Name == "_" {
+			return ""
+		}
+		return v.Name
+	case *ast.SelectorExpr:
+		if _, ok := v.X.(*ast.Ident); ok {
+			return v.Sel.Name // a field of the gui struct, like "g.name"
+		}
+	}
+
+	return ""
+}
+
+func (i *goImporter) lookupVar(e ast.Expr) *goVar {
+	switch v := e.(type) {
+	case *ast.Ident:
+		return i.vars[v.Name]
+	case *ast.SelectorExpr:
+		if _, ok := v.X.(*ast.Ident); !ok {
+			return nil
+		}
+		if _, ok := i.vars[v.X.(*ast.Ident).Name]; ok {
+			return nil
+		}
+		return i.vars[v.Sel.Name]
+	}
+
+	return nil
+}
+
+func (i *goImporter) resolveVar(name string, v *goVar) (fyne.CanvasObject, error) {
+	if v.obj != nil {
+		return v.obj, nil
+	}
+	if v.resolving {
+		return nil, i.errorf(v.expr, "variable %s refers to itself", name)
+	}
+
+	v.resolving = true
+	obj, err := i.object(v.expr)
+	v.resolving = false
+	if err != nil {
+		return nil, err
+	}
+	if obj == nil {
+		return nil, nil
+	}
+
+	v.obj = obj
+	i.props(obj)["name"] = name
+	for _, op := range v.ops {
+		if err := i.applyOp(obj, op); err != nil {
+			return nil, err
+		}
+	}
+	return obj, nil
+}
+
+func (i *goImporter) applyOp(obj fyne.CanvasObject, op ast.Stmt) error {
+	switch stmt := op.(type) {
+	case *ast.AssignStmt:
+		field := stmt.Lhs[0].(*ast.SelectorExpr).Sel.Name
+		if strings.HasPrefix(field, "On") && isAction(stmt.Rhs[0]) {
+			i.setAction(obj, field, stmt.Rhs[0])
+			return nil
+		}
+
+		f := reflect.ValueOf(obj).Elem().FieldByName(field)
+		if !f.IsValid() || !f.CanSet() {
+			return i.errorf(stmt, "unknown field %s", field)
+		}
+		val, err := i.value(stmt.Rhs[0], f.Type())
+		if err != nil {
+			return err
+		}
+		f.Set(val)
+		if colorName, ok := i.themeColor(stmt.Rhs[0]); ok {
+			i.props(obj)[field] = colorName
+		}
+	case *ast.ExprStmt:
+		call := stmt.X.(*ast.CallExpr)
+		name := call.Fun.(*ast.SelectorExpr).Sel.Name
+		m := reflect.ValueOf(obj).MethodByName(name)
+		if !m.IsValid() || !strings.HasPrefix(name, "Set") {
+			return nil // other method calls do not change the design
+		}
+
+		_, err := i.call(m, call)
+		return err
+	}
+
+	return nil
+}
+
+func (i *goImporter) object(e ast.Expr) (fyne.CanvasObject, error) {
+	start := len(i.texts)
+	obj, err := i.createObject(e)
+	if obj != nil {
+		i.translated(obj, start)
+	}
+	return obj, err
+}
+
+func (i *goImporter) createObject(e ast.Expr) (fyne.CanvasObject, error) {
+	switch v := e.(type) {
+	case *ast.ParenExpr:
+		return i.object(v.X)
+	case *ast.Ident:
+		if v.Name == "nil" {
+			return nil, nil
+		}
+		if vv, ok := i.vars[v.Name]; ok {
+			return i.resolveVar(v.Name, vv)
+		}
+		return nil, i.errorf(v, "unknown variable %s", v.Name)
+	case *ast.SelectorExpr:
+		if vv := i.lookupVar(v); vv != nil {
+			return i.resolveVar(v.Sel.Name, vv)
+		}
+	case *ast.UnaryExpr:
+		if lit, ok := v.X.(*ast.CompositeLit); ok && v.Op == token.AND {
+			return i.structObject(lit)
+		}
+	case *ast.CallExpr:
+		name := i.funcName(v.Fun)
+		if name == "container.New" {
+			if len(v.Args) == 0 {
+				return nil, i.errorf(v, "missing layout")
+			}
+			lay, ok := v.Args[0].(*ast.CallExpr)
+			if !ok {
+				return nil, i.errorf(v.Args[0], "unsupported layout %s", i.source(v.Args[0]))
+			}
+			return i.container(i.funcName(lay.Fun), lay, lay.Args, v.Args[1:])
+		}
+		if strings.HasPrefix(name, "container.") {
+			if _, ok := importLayouts[name]; ok {
+				return i.container(name, v, nil, v.Args)
+			}
+		}
+		if class, ok := importDefaults[name]; ok {
+			obj := guidefs.Lookup(class).Create()
+			i.props(obj)
+			return obj, nil
+		}
+
+		if fn, ok := importBindings[name]; ok {
+			return i.boundObject(fn, v)
+		}
+
+		fn, ok := importFuncs[name]
+		if !ok {
+			break
+		}
+		ret, err := i.call(reflect.ValueOf(fn), v)
+		if err != nil {
+			return nil, err
+		}
+		obj, ok := ret.Interface().(fyne.CanvasObject)
+		if !ok {
+			return nil, i.errorf(v, "%s does not return a CanvasObject", name)
+		}
+		if action, ok := importActions[name]; ok {
+			for _, arg := range v.Args {
+				if isAction(arg) {
+					i.setAction(obj, action, arg)
+				}
+			}
+		}
+		i.props(obj)
+		return obj, nil
+	}
+
+	return nil, i.errorf(e, "unsupported expression %s", i.source(e))
+}
+
+// boundObject creates a widget from a data bound constructor and records the name of the binding.
+func (i *goImporter) boundObject(fn interface{}, v *ast.CallExpr) (fyne.CanvasObject, error) {
+	if len(v.Args) == 0 {
+		return nil, i.errorf(v, "missing binding")
+	}
+
+	var bind string
+	switch arg := v.Args[len(v.Args)-1].(type) {
+	case *ast.Ident:
+		bind = arg.Name
+	case *ast.SelectorExpr:
+		bind = arg.Sel.Name
+	default:
+		return nil, i.errorf(arg, "unsupported binding %s", i.source(arg))
+	}
+
+	create := *v
+	create.Args = v.Args[:len(v.Args)-1]
+	ret, err := i.call(reflect.ValueOf(fn), &create)
+	if err != nil {
+		return nil, err
+	}
+	obj := ret.Interface().(fyne.CanvasObject)
+	i.props(obj)["binding"] = bind
+	return obj, nil
+}
+
+func (i *goImporter) container(name string, lay *ast.CallExpr, layArgs, children []ast.Expr) (fyne.CanvasObject, error) {
+	layName, ok := importLayouts[name]
+	if !ok {
+		return nil, i.errorf(lay, "unsupported layout %s", name)
+	}
+	if layName == "Border" && layArgs == nil { // container.NewBorder has the edges as the first arguments
+		if len(children) < 4 {
+			return nil, i.errorf(lay, "border requires 4 edge parameters")
+		}
+		layArgs, children = children[:4], children[4:]
+	} else if (layName == "Grid" || layName == "GridWrap") && layArgs == nil {
+		if len(children) < 1 {
+			return nil, i.errorf(lay, "%s requires a size parameter", name)
+		}
+		layArgs, children = children[:1], children[1:]
+	}
+
+	obj := &fyne.Container{}
+	props := map[string]string{"layout": layName}
+	for _, c := range children {
+		child, err := i.object(c)
+		if err != nil {
+			return nil, err
+		}
+		if child != nil {
+			obj.Objects = append(obj.Objects, child)
+		}
+	}
+
+	switch layName {
+	case "Border":
+		if len(layArgs) != 4 {
+			return nil, i.errorf(lay, "border requires 4 edge parameters")
+		}
+		for pos, edge := range []string{"top", "bottom", "left", "right"} {
+			child, err := i.object(layArgs[pos])
+			if err != nil {
+				return nil, err
+			}
+			if child == nil {
+				continue
+			}
+
+			found := false
+			for _, o := range obj.Objects {
+				found = found || o == child
+			}
+			if !found {
+				obj.Objects = append(obj.Objects, child)
+			}
+			props[edge] = guidefs.NodeID(child, i.meta)
+		}
+	case "Grid":
+		if len(layArgs) != 1 {
+			return nil, i.errorf(lay, "grid requires a count parameter")
+		}
+		count, err := i.value(layArgs[0], reflect.TypeOf(0))
+		if err != nil {
+			return nil, err
+		}
+		props["count"] = strconv.Itoa(int(count.Int()))
+		props["grid_type"] = "Columns"
+		if strings.HasSuffix(name, "Rows") {
+			props["grid_type"] = "Rows"
+		}
+	case "GridWrap":
+		if len(layArgs) != 1 {
+			return nil, i.errorf(lay, "grid wrap requires a size parameter")
+		}
+		size, err := i.value(layArgs[0], reflect.TypeOf(fyne.Size{}))
+		if err != nil {
+			return nil, err
+		}
+		s := size.Interface().(fyne.Size)
+		props["width"] = strconv.Itoa(int(s.Width))
+		props["height"] = strconv.Itoa(int(s.Height))
+	case "HBox":
+		props["dir"] = "horizontal"
+	case "VBox":
+		props["dir"] = "vertical"
+	}
+
+	i.meta[obj] = props
+	obj.Layout = guidefs.Layouts[layName].Create(obj, i.meta)
+	return obj, nil
+}
+
+func (i *goImporter) structObject(lit *ast.CompositeLit) (fyne.CanvasObject, error) {
+	sel, ok := lit.Type.(*ast.SelectorExpr)
+	if !ok {
+		return nil, i.errorf(lit, "unsupported type %s", i.source(lit.Type))
+	}
+	class := "*" + i.funcName(sel)
+	info := guidefs.Lookup(class)
+	if info == nil {
+		return nil, i.errorf(lit, "unknown class %s", class)
+	}
+
+	t := reflect.TypeOf(info.Create()).Elem()
+	val := reflect.New(t)
+	actions, err := i.setFields(val.Elem(), lit)
+	if err != nil {
+		return nil, err
+	}
+
+	obj := val.Interface().(fyne.CanvasObject)
+	for k, v := range actions {
+		i.setAction(obj, k, v)
+	}
+	props := i.props(obj)
+	for _, elt := range lit.Elts {
+		kv := elt.(*ast.KeyValueExpr)
+		if colorName, ok := i.themeColor(kv.Value); ok {
+			props[kv.Key.(*ast.Ident).Name] = colorName
+		}
+	}
+	return obj, nil
+}
+
+// projectResource loads a project file referenced by the generated `loadResource` helper, like `g.loadResource("logo.svg")`.
+func (i *goImporter) projectResource(call *ast.CallExpr) (fyne.Resource, bool, error) {
+	sel, ok := call.Fun.(*ast.SelectorExpr)
+	if !ok || sel.Sel.Name != "loadResource" || len(call.Args) != 1 {
+		return nil, false, nil
+	}
+	lit, ok := call.Args[0].(*ast.BasicLit)
+	if !ok || lit.Kind != token.STRING {
+		return nil, true, i.errorf(call, "resource path must be a string")
+	}
+	name, err := strconv.Unquote(lit.Value)
+	if err != nil {
+		return nil, true, i.errorf(lit, "invalid string %s", lit.Value)
+	}
+
+	res, err := guidefs.LoadProjectResource(name)
+	if err != nil {
+		return nil, true, i.errorf(call, "failed to load resource %q: %v", name, err)
+	}
+	return res, true, nil
+}
+
+// translation returns the source string of text that is looked up with the lang package,
+// like `lang.L("Save")` or `lang.X("save.button", "Save")`, and remembers its key.
+func (i *goImporter) translation(call *ast.CallExpr) (string, bool, error) {
+	name := i.funcName(call.Fun)
+	if name != "lang.L" && name != "lang.X" {
+		return "", false, nil
+	}
+	if (name == "lang.L" && len(call.Args) != 1) || (name == "lang.X" && len(call.Args) != 2) {
+		return "", true, i.errorf(call, "unsupported translation %s", i.source(call))
+	}
+
+	var args []string
+	for _, arg := range call.Args {
+		lit, ok := arg.(*ast.BasicLit)
+		if !ok || lit.Kind != token.STRING {
+			return "", true, i.errorf(arg, "translation must be a string")
+		}
+		s, err := strconv.Unquote(lit.Value)
+		if err != nil {
+			return "", true, i.errorf(lit, "invalid string %s", lit.Value)
+		}
+		args = append(args, s)
+	}
+
+	i.texts = append(i.texts, goText{key: args[0], text: args[len(args)-1]})
+	return args[len(args)-1], true, nil
+}
+
+// translated stores the translation keys of the strings looked up since start, for the text fields of obj that use them.
+func (i *goImporter) translated(obj fyne.CanvasObject, start int) {
+	texts := i.texts[start:]
+	i.texts = i.texts[:start]
+	if len(texts) == 0 {
+		return
+	}
+
+	val := reflect.ValueOf(obj).Elem()
+	for _, field := range guidefs.TranslatableFields[reflect.TypeOf(obj).String()] {
+		text := val.FieldByName(field).String()
+		for _, t := range texts {
+			if t.text == text {
+				guidefs.SetTranslationKey(i.props(obj), field, t.key)
+				break
+			}
+		}
+	}
+}
+
+// themeColor returns the color name if the expression looks up a theme color, like `theme.Color(theme.ColorNamePrimary)`.
+func (i *goImporter) themeColor(e ast.Expr) (string, bool) {
+	call, ok := e.(*ast.CallExpr)
+	if !ok || i.funcName(call.Fun) != "theme.Color" || len(call.Args) != 1 {
+		return "", false
+	}
+
+	switch arg := call.Args[0].(type) {
+	case *ast.SelectorExpr:
+		if name := i.funcName(arg); strings.HasPrefix(name, "theme.") {
+			return guidefs.ThemeColorForConst(strings.TrimPrefix(name, "theme."))
+		}
+	case *ast.BasicLit:
+		if name, err := strconv.Unquote(arg.Value); err == nil && arg.Kind == token.STRING {
+			return name, true
+		}
+	}
+	return "", false
+}
+
+func (i *goImporter) setFields(val reflect.Value, lit *ast.CompositeLit) (map[string]ast.Expr, error) {
+	actions := make(map[string]ast.Expr)
+	for _, elt := range lit.Elts {
+		kv, ok := elt.(*ast.KeyValueExpr)
+		if !ok {
+			return nil, i.errorf(elt, "struct fields must be named")
+		}
+		key, ok := kv.Key.(*ast.Ident)
+		if !ok {
+			return nil, i.errorf(kv.Key, "unsupported field name")
+		}
+
+		f := val.FieldByName(key.Name)
+		if !f.IsValid() || !f.CanSet() {
+			return nil, i.errorf(kv, "unknown field %s", key.Name)
+		}
+		if f.Kind() == reflect.Func && isAction(kv.Value) {
+			if strings.HasPrefix(key.Name, "On") {
+				actions[key.Name] = kv.Value
+			}
+			continue
+		}
+
+		v, err := i.value(kv.Value, f.Type())
+		if err != nil {
+			return nil, err
+		}
+		f.Set(v)
+	}
+
+	return actions, nil
+}
+
+func (i *goImporter) value(e ast.Expr, t reflect.Type) (reflect.Value, error) {
+	if t == canvasObjectType {
+		obj, err := i.object(e)
+		if err != nil || obj == nil {
+			return reflect.Zero(t), err
+		}
+		return reflect.ValueOf(obj), nil
+	}
+
+	switch v := e.(type) {
+	case *ast.ParenExpr:
+		return i.value(v.X, t)
+	case *ast.Ident:
+		switch v.Name {
+		case "nil":
+			return reflect.Zero(t), nil
+		case "true", "false":
+			return i.convert(reflect.ValueOf(v.Name == "true"), t, v)
+		}
+		if vv, ok := i.vars[v.Name]; ok {
+			obj, err := i.resolveVar(v.Name, vv)
+			if err != nil {
+				return reflect.Value{}, err
+			}
+			return i.convert(reflect.ValueOf(obj), t, v)
+		}
+	case *ast.BasicLit:
+		switch v.Kind {
+		case token.STRING:
+			s, err := strconv.Unquote(v.Value)
+			if err != nil {
+				return reflect.Value{}, i.errorf(v, "invalid string %s", v.Value)
+			}
+			return i.convert(reflect.ValueOf(s), t, v)
+		case token.INT, token.FLOAT:
+			f, err := strconv.ParseFloat(v.Value, 64)
+			if err != nil {
+				n, err2 := strconv.ParseInt(v.Value, 0, 64)
+				if err2 != nil {
+					return reflect.Value{}, i.errorf(v, "invalid number %s", v.Value)
+				}
+				f = float64(n)
+			}
+			return i.convert(reflect.ValueOf(f), t, v)
+		}
+	case *ast.UnaryExpr:
+		switch v.Op {
+		case token.SUB:
+			val, err := i.value(v.X, t)
+			if err != nil {
+				return val, err
+			}
+			neg := reflect.New(t).Elem()
+			switch {
+			case val.CanInt():
+				neg.SetInt(-val.Int())
+			case val.CanFloat():
+				neg.SetFloat(-val.Float())
+			default:
+				return reflect.Value{}, i.errorf(v, "cannot negate %s", t)
+			}
+			return neg, nil
+		case token.AND:
+			lit, ok := v.X.(*ast.CompositeLit)
+			if !ok {
+				break
+			}
+			if t.Implements(canvasObjectType) {
+				obj, err := i.object(v)
+				if err != nil {
+					return reflect.Value{}, err
+				}
+				return reflect.ValueOf(obj), nil
+			}
+
+			elem := t
+			if t.Kind() == reflect.Ptr {
+				elem = t.Elem()
+			}
+			val, err := i.composite(lit, elem)
+			if err != nil {
+				return val, err
+			}
+			ptr := reflect.New(val.Type())
+			ptr.Elem().Set(val)
+			return i.convert(ptr, t, v)
+		}
+	case *ast.CompositeLit:
+		val, err := i.composite(v, t)
+		if err != nil {
+			return val, err
+		}
+		return i.convert(val, t, v)
+	case *ast.SelectorExpr:
+		if t.Kind() == reflect.Func && isAction(v) {
+			return reflect.Zero(t), nil // handler methods are stored as actions by the caller
+		}
+		if vv := i.lookupVar(v); vv != nil {
+			obj, err := i.resolveVar(v.Sel.Name, vv)
+			if err != nil {
+				return reflect.Value{}, err
+			}
+			return i.convert(reflect.ValueOf(obj), t, v)
+		}
+		if c, ok := importConsts[i.funcName(v)]; ok {
+			return i.convert(reflect.ValueOf(c), t, v)
+		}
+	case *ast.FuncLit:
+		return reflect.Zero(t), nil // callbacks are stored as actions by the caller
+	case *ast.CallExpr:
+		if _, ok := v.Fun.(*ast.ParenExpr); ok && len(v.Args) == 1 { // a conversion such as (*url.Userinfo)(nil)
+			if id, ok := v.Args[0].(*ast.Ident); ok && id.Name == "nil" {
+				return reflect.Zero(t), nil
+			}
+		}
+
+		if res, ok, err := i.projectResource(v); ok {
+			if err != nil {
+				return reflect.Value{}, err
+			}
+			return i.convert(reflect.ValueOf(res), t, v)
+		}
+		if colorName, ok := i.themeColor(v); ok {
+			return i.convert(reflect.ValueOf(guidefs.ThemeColor(colorName)), t, v)
+		}
+		if text, ok, err := i.translation(v); ok {
+			if err != nil {
+				return reflect.Value{}, err
+			}
+			return i.convert(reflect.ValueOf(text), t, v)
+		}
+		name := i.funcName(v.Fun)
+		if strings.HasPrefix(name, "theme.") && len(v.Args) == 0 {
+			if res, ok := guidefs.Icons[strings.TrimPrefix(name, "theme.")]; ok {
+				return i.convert(reflect.ValueOf(res), t, v)
+			}
+		}
+		if t.Implements(canvasObjectType) {
+			obj, err := i.object(v)
+			if err != nil {
+				return reflect.Value{}, err
+			}
+			return i.convert(reflect.ValueOf(obj), t, v)
+		}
+		if fn, ok := importFuncs[name]; ok {
+			ret, err := i.call(reflect.ValueOf(fn), v)
+			if err != nil {
+				return ret, err
+			}
+			return i.convert(ret, t, v)
+		}
+	}
+
+	return reflect.Value{}, i.errorf(e, "unsupported value %s", i.source(e))
+}
+
+func (i *goImporter) composite(lit *ast.CompositeLit, t reflect.Type) (reflect.Value, error) {
+	if lit.Type != nil {
+		if sel, ok := lit.Type.(*ast.SelectorExpr); ok {
+			if typ, ok := importTypes[i.funcName(sel)]; ok {
+				t = typ
+			}
+		}
+	}
+
+	switch t.Kind() {
+	case reflect.Slice:
+		ret := reflect.MakeSlice(t, 0, len(lit.Elts))
+		for _, elt := range lit.Elts {
+			v, err := i.value(elt, t.Elem())
+			if err != nil {
+				return reflect.Value{}, err
+			}
+			ret = reflect.Append(ret, v)
+		}
+		return ret, nil
+	case reflect.Struct:
+		ret := reflect.New(t).Elem()
+		_, err := i.setFields(ret, lit)
+		return ret, err
+	}
+
+	return reflect.Value{}, i.errorf(lit, "unsupported literal %s", i.source(lit))
+}
+
+func (i *goImporter) call(fn reflect.Value, call *ast.CallExpr) (ret reflect.Value, err error) {
+	ft := fn.Type()
+	if (!ft.IsVariadic() && len(call.Args) != ft.NumIn()) || (ft.IsVariadic() && len(call.Args) < ft.NumIn()-1) {
+		return reflect.Value{}, i.errorf(call, "wrong number of parameters to %s", i.source(call.Fun))
+	}
+	if ft.NumOut() == 0 {
+		ret = reflect.ValueOf(struct{}{})
+	}
+
+	args := make([]reflect.Value, len(call.Args))
+	for id, arg := range call.Args {
+		t := ft.In(id)
+		if ft.IsVariadic() && id >= ft.NumIn()-1 {
+			t = ft.In(ft.NumIn() - 1).Elem()
+		}
+
+		args[id], err = i.value(arg, t)
+		if err != nil {
+			return reflect.Value{}, err
+		}
+	}
+
+	defer func() {
+		if r := recover(); r != nil {
+			err = i.errorf(call, "failed to call %s: %v", i.source(call.Fun), r)
+		}
+	}()
+	out := fn.Call(args)
+	if len(out) > 0 {
+		ret = out[0]
+	}
+	return ret, nil
+}
+
+func (i *goImporter) setAction(obj fyne.CanvasObject, name string, fn ast.Expr) {
+	f, isForm := obj.(*widget.Form)
+	if lit, ok := fn.(*ast.FuncLit); ok && len(lit.Body.List) == 0 && !isForm {
+		return // empty callbacks are the default, but show the buttons of a form
+	}
+
+	i.props(obj)[name] = i.source(fn)
+	if isForm {
+		guidefs.UpdateFormActions(f, i.props(obj))
+	}
+}
+
+// isAction returns true if the expression is a callback that is stored as an action,
+// either a function literal or a handler method such as `g.OnSaveTapped`.
+func isAction(e ast.Expr) bool {
+	switch v := e.(type) {
+	case *ast.FuncLit:
+		return true
+	case *ast.SelectorExpr:
+		id, ok := v.X.(*ast.Ident)
+		return ok && id.Name == "g"
+	}
+	return false
+}
+
+func (i *goImporter) props(obj fyne.CanvasObject) map[string]string {
+	props, ok := i.meta[obj]
+	if !ok {
+		props = make(map[string]string)
+		i.meta[obj] = props
+	}
+	return props
+}
+
+// funcName returns the qualified name, like "widget.NewLabel", using the package names of the imports.
+func (i *goImporter) funcName(e ast.Expr) string {
+	sel, ok := e.(*ast.SelectorExpr)
+	if !ok {
+		return ""
+	}
+	pkg, ok := sel.X.(*ast.Ident)
+	if !ok {
+		return ""
+	}
+
+	name := pkg.Name
+	if p, ok := i.pkgs[name]; ok {
+		name = p
+	}
+	return name + "." + sel.Sel.Name
+}
+
+func (i *goImporter) source(n ast.Node) string {
+	start := i.fset.Position(n.Pos()).Offset
+	end := i.fset.Position(n.End()).Offset
+	if start < 0 || end > len(i.src) || start > end {
+		return ""
+	}
+
+	return string(i.src[start:end])
+}
+
+func (i *goImporter) errorf(n ast.Node, format string, args ...interface{}) error {
+	return fmt.Errorf("%s: %s", i.fset.Position(n.Pos()), fmt.Sprintf(format, args...))
+}
+
+func (i *goImporter) convert(v reflect.Value, t reflect.Type, n ast.Node) (reflect.Value, error) {
+	if !v.IsValid() {
+		return reflect.Zero(t), nil
+	}
+	if v.Type().AssignableTo(t) {
+		return v, nil
+	}
+	if !v.Type().ConvertibleTo(t) || (v.Kind() != reflect.String && t.Kind() == reflect.String) {
+		return reflect.Value{}, i.errorf(n, "cannot use %s as %s", i.source(n), t)
+	}
+
+	return v.Convert(t), nil
+}
@@ -0,0 +1,137 @@
+package gui
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+
+	"github.com/fyne-io/defyne/internal/guidefs"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const legacyUI = `package main
+
+import (
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/theme"
+	w "fyne.io/fyne/v2/widget"
+)
+
+type gui struct {
+	title *w.Label
+}
+
+func (g *gui) makeUI() fyne.CanvasObject {
+	g.title = w.NewLabel("Welcome")
+	g.title.Alignment = fyne.TextAlignCenter
+	name := w.NewEntry()
+	name.SetPlaceHolder("Your name")
+
+	save := w.NewButtonWithIcon("Save", theme.DocumentSaveIcon(), func() {
+		println("saved")
+	})
+	save.Importance = w.HighImportance
+
+	return container.NewBorder(g.title, save, nil, nil,
+		&container.Split{Horizontal: true, Offset: 0.25,
+			Leading:  container.NewGridWithColumns(2, w.NewLabel("Name"), name),
+			Trailing: container.NewAppTabs(container.NewTabItem("Tab", w.NewSeparator())),
+		})
+}
+`
+
+func TestImportGo(t *testing.T) {
+	obj, meta, err := ImportGo(strings.NewReader(legacyUI), "")
+	require.Nil(t, err)
+
+	border, ok := obj.(*fyne.Container)
+	require.True(t, ok)
+	assert.Equal(t, "Border", meta[border]["layout"])
+	require.Equal(t, 3, len(border.Objects))
+	assert.Equal(t, meta[border.Objects[1]]["id"], meta[border]["top"])
+	assert.Equal(t, meta[border.Objects[2]]["id"], meta[border]["bottom"])
+	assert.NotEqual(t, meta[border]["top"], meta[border]["bottom"])
+	assert.Equal(t, "", meta[border]["left"])
+
+	title := border.Objects[1].(*widget.Label)
+	assert.Equal(t, "Welcome", title.Text)
+	assert.Equal(t, fyne.TextAlignCenter, title.Alignment)
+	assert.Equal(t, "title", meta[title]["name"])
+
+	save := border.Objects[2].(*widget.Button)
+	assert.Equal(t, widget.HighImportance, save.Importance)
+	assert.Equal(t, theme.DocumentSaveIcon(), save.Icon)
+	assert.Equal(t, "save", meta[save]["name"])
+	assert.Equal(t, "func() {\n\t\tprintln(\"saved\")\n\t}", meta[save]["OnTapped"])
+
+	split := border.Objects[0].(*container.Split)
+	assert.True(t, split.Horizontal)
+	assert.Equal(t, 0.25, split.Offset)
+	grid := split.Leading.(*fyne.Container)
+	assert.Equal(t, "Grid", meta[grid]["layout"])
+	assert.Equal(t, "2", meta[grid]["count"])
+	assert.Equal(t, "Columns", meta[grid]["grid_type"])
+	name := grid.Objects[1].(*widget.Entry)
+	assert.Equal(t, "Your name", name.PlaceHolder)
+	tabs := split.Trailing.(*container.AppTabs)
+	assert.Equal(t, "Tab", tabs.Items[0].Text)
+	assert.NotNil(t, meta[tabs.Items[0].Content])
+}
+
+func TestImportGo_Errors(t *testing.T) {
+	_, _, err := ImportGo(strings.NewReader("package main\n"), "")
+	assert.NotNil(t, err)
+
+	_, _, err = ImportGo(strings.NewReader(`package main
+
+func makeUI() fyne.CanvasObject {
+	return myWidget()
+}`), "makeUI")
+	require.NotNil(t, err)
+	assert.Contains(t, err.Error(), "4:9")
+}
+
+func TestImportGo_BorderArguments(t *testing.T) {
+	for _, expr := range []string{
+		`container.New(layout.NewBorderLayout(nil), widget.NewLabel("A"))`,
+		`container.New(layout.NewBorderLayout(nil, nil, nil))`,
+		`container.NewBorder(nil, nil, widget.NewLabel("A"))`,
+	} {
+		_, _, err := ImportGo(strings.NewReader(`package main
+
+func makeUI() fyne.CanvasObject {
+	return `+expr+`
+}`), "makeUI")
+		require.NotNil(t, err, expr)
+		assert.Contains(t, err.Error(), "border requires 4 edge parameters", expr)
+	}
+}
+
+func TestImportGo_Exported(t *testing.T) {
+	guidefs.InitOnce()
+	l := widget.NewLabelWithStyle("Hi", fyne.TextAlignCenter, fyne.TextStyle{Bold: true})
+	b := widget.NewButton("Go", nil)
+	c := container.NewHBox(l, b)
+	meta := map[fyne.CanvasObject]map[string]string{
+		l: {"name": "greeting"},
+		b: {"name": "goButton", "OnTapped": "func() { println(\"tapped\") }"},
+		c: {"layout": "HBox", "dir": "horizontal"},
+	}
+
+	var code bytes.Buffer
+	require.Nil(t, ExportGo(c, meta, "main", &code))
+	obj, meta2, err := ImportGo(&code, "")
+	require.Nil(t, err)
+
+	var want, got bytes.Buffer
+	require.Nil(t, EncodeObject(c, meta, &want))
+	require.Nil(t, EncodeObject(obj, meta2, &got))
+	assert.Equal(t, want.String(), got.String())
+}
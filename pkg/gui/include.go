@@ -0,0 +1,165 @@
+package gui
+
+import (
+	"bytes"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"fyne.io/fyne/v2"
+
+	"github.com/fyne-io/defyne/internal/guidefs"
+)
+
+// includeType is the type of a node that includes another design file, using the path in its "Src" field.
+const includeType = "include"
+
+type includeObj struct {
+	Type string
+	Name string `json:",omitempty"`
+	ID   string `json:",omitempty"`
+	Src  string
+}
+
+func init() {
+	guidefs.LoadInclude = func(src string) (fyne.CanvasObject, map[fyne.CanvasObject]map[string]string, error) {
+		return loadInclude(src, nil)
+	}
+}
+
+// include returns an object that shows the included design, or a placeholder if it could not be loaded.
+func (d *decoder) include(src, path string) *guidefs.Include {
+	if src == "" {
+		return guidefs.NewInclude(src, nil, nil)
+	}
+	for _, parent := range d.includes {
+		if parent == src {
+			d.fail(path, "include cycle: %s -> %s", strings.Join(d.includes, " -> "), src)
+			return guidefs.NewInclude(src, nil, nil)
+		}
+	}
+
+	includes := append(append([]string{}, d.includes...), src)
+	content, meta, err := loadInclude(src, includes)
+	if err != nil {
+		d.fail(path, "failed to include %q: %v", src, err)
+	}
+	return guidefs.NewInclude(src, content, meta)
+}
+
+// loadInclude decodes the design file at a path relative to the project root.
+// Any objects that could be decoded are returned along with an error describing the problems.
+func loadInclude(src string, includes []string) (fyne.CanvasObject, map[fyne.CanvasObject]map[string]string, error) {
+	res, err := guidefs.LoadProjectResource(src)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	d := &decoder{meta: make(map[fyne.CanvasObject]map[string]string), includes: includes}
+	obj, err := d.decodeDocument(bytes.NewReader(res.Content()))
+	if err != nil {
+		return nil, nil, err
+	}
+	return obj, d.meta, d.err()
+}
+
+// includesRequired returns the included designs in the object tree, in tree order.
+func includesRequired(obj fyne.CanvasObject, ret []*guidefs.Include) []*guidefs.Include {
+	switch c := obj.(type) {
+	case nil:
+		return ret
+	case *guidefs.Include:
+		return append(ret, c)
+	case *fyne.Container:
+		for _, w := range c.Objects {
+			ret = includesRequired(w, ret)
+		}
+	default:
+		if info := guidefs.Lookup(reflect.TypeOf(obj).String()); info != nil && info.IsContainer() {
+			for _, w := range info.Children(obj) {
+				ret = includesRequired(w, ret)
+			}
+		}
+	}
+	return ret
+}
+
+// includeMeta returns a copy of the metadata where every included design is named,
+// so that each one is stored in a field of the gui type.
+func includeMeta(obj fyne.CanvasObject, meta map[fyne.CanvasObject]map[string]string) map[fyne.CanvasObject]map[string]string {
+	includes := includesRequired(obj, nil)
+	if len(includes) == 0 {
+		return meta
+	}
+
+	ret := make(map[fyne.CanvasObject]map[string]string, len(meta))
+	var names []string
+	for o, props := range meta {
+		copied := make(map[string]string, len(props))
+		for k, v := range props {
+			copied[k] = v
+		}
+		ret[o] = copied
+		if props["name"] != "" {
+			names = append(names, props["name"])
+		}
+	}
+
+	for _, inc := range includes {
+		component := guidefs.IncludeComponent(inc.Src)
+		if component == "" || ret[inc]["name"] != "" {
+			continue
+		}
+
+		name := component
+		for i := 2; containsString(names, name); i++ {
+			name = component + strconv.Itoa(i)
+		}
+		names = append(names, name)
+		if ret[inc] == nil {
+			ret[inc] = make(map[string]string)
+		}
+		ret[inc]["name"] = name
+	}
+	return ret
+}
+
+// includePreviews returns the Go code of the designs included in the tree, so that a preview can be run
+// without the code generated for those files. Any packages that the code uses are added to pkgs.
+func includePreviews(obj fyne.CanvasObject, pkgs []string) (string, []string) {
+	code := ""
+	var done []string
+	var preview func(fyne.CanvasObject)
+	preview = func(obj fyne.CanvasObject) {
+		for _, inc := range includesRequired(obj, nil) {
+			component := guidefs.IncludeComponent(inc.Src)
+			content, meta := inc.Content()
+			if content == nil || component == "main" || containsString(done, component) {
+				continue
+			}
+			done = append(done, component)
+
+			meta = includeMeta(content, meta)
+			incCode, incPkgs, err := exportCode(packagesRequired(content, meta), varsRequired(content, meta), content, meta,
+				component, projectDir(), nil)
+			if err != nil {
+				fyne.LogError("Failed to preview included design "+inc.Src, err)
+				continue
+			}
+			for _, p := range incPkgs {
+				if !containsString(pkgs, p) {
+					pkgs = append(pkgs, p)
+				}
+			}
+
+			if end := strings.Index(incCode, "\n)\n"); end >= 0 {
+				incCode = incCode[end+3:] // remove the package and imports
+			}
+			code += incCode
+			preview(content)
+		}
+	}
+	preview(obj)
+
+	return code, pkgs
+}
@@ -0,0 +1,151 @@
+package gui
+
+import (
+	"bytes"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/storage"
+	"fyne.io/fyne/v2/widget"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/fyne-io/defyne/internal/guidefs"
+)
+
+const headerJSON = `{
+  "Version": 1,
+  "Object": {
+    "Type": "*fyne.Container",
+    "Layout": "HBox",
+    "Name": "bar",
+    "Objects": [
+      {"Type": "*widget.Label", "Name": "title", "Struct": {"Text": "Header"}}
+    ]
+  }
+}`
+
+const includeJSON = `{
+  "Version": 1,
+  "Object": {
+    "Type": "*fyne.Container",
+    "Layout": "VBox",
+    "Objects": [
+      {"Type": "include", "Src": "header.gui.json"},
+      {"Type": "*widget.Button", "Struct": {"Text": "Go"}}
+    ]
+  }
+}`
+
+func setupIncludes(t *testing.T, files map[string]string) {
+	dir := t.TempDir()
+	for name, content := range files {
+		require.Nil(t, os.WriteFile(filepath.Join(dir, name), []byte(content), 0644))
+	}
+
+	SetProjectRoot(storage.NewFileURI(dir))
+	t.Cleanup(func() {
+		SetProjectRoot(nil)
+	})
+}
+
+func TestInclude_EncodeDecode(t *testing.T) {
+	setupIncludes(t, map[string]string{"header.gui.json": headerJSON})
+
+	obj, meta, err := DecodeObject(strings.NewReader(includeJSON))
+	require.Nil(t, err)
+	inc := obj.(*fyne.Container).Objects[0].(*guidefs.Include)
+	assert.Equal(t, "header.gui.json", inc.Src)
+	content, incMeta := inc.Content()
+	require.NotNil(t, content)
+	title := content.(*fyne.Container).Objects[0].(*widget.Label)
+	assert.Equal(t, "Header", title.Text)
+	assert.Equal(t, "title", incMeta[title]["name"])
+	_, ok := meta[title]
+	assert.False(t, ok) // included objects are not part of the design
+
+	var buf bytes.Buffer
+	require.Nil(t, EncodeObject(obj, meta, &buf))
+	assert.Contains(t, buf.String(), `"Type": "include"`)
+	assert.Contains(t, buf.String(), `"Src": "header.gui.json"`)
+	assert.NotContains(t, buf.String(), "Header")
+}
+
+func TestInclude_Errors(t *testing.T) {
+	setupIncludes(t, map[string]string{
+		"a.gui.json": `{"Version": 1, "Object": {"Type": "include", "Src": "b.gui.json"}}`,
+		"b.gui.json": `{"Version": 1, "Object": {"Type": "include", "Src": "a.gui.json"}}`,
+	})
+
+	_, _, err := DecodeObject(strings.NewReader(`{"Version": 1, "Object": {"Type": "include", "Src": "a.gui.json"}}`))
+	require.NotNil(t, err)
+	assert.Contains(t, err.Error(), "include cycle: a.gui.json -> b.gui.json -> a.gui.json")
+
+	obj, _, err := DecodeObjectLenient(strings.NewReader(`{"Version": 1, "Object": {"Type": "include", "Src": "missing.gui.json"}}`))
+	require.NotNil(t, err)
+	assert.Contains(t, err.Error(), `failed to include "missing.gui.json"`)
+	inc := obj.(*guidefs.Include)
+	assert.Equal(t, "missing.gui.json", inc.Src)
+}
+
+func TestInclude_ExportGo(t *testing.T) {
+	setupIncludes(t, map[string]string{"header.gui.json": headerJSON})
+
+	obj, meta, err := DecodeObject(strings.NewReader(includeJSON))
+	require.Nil(t, err)
+
+	var buf bytes.Buffer
+	require.Nil(t, ExportGo(obj, meta, "main", &buf))
+	code := buf.String()
+	assert.Contains(t, code, "\theader *headerGui\n")
+	assert.Contains(t, code, "g.header = newHeaderGUI()\n")
+	assert.Contains(t, code, "g.header.makeUI(),")
+	assert.NotContains(t, code, `"Header"`)
+	assert.Equal(t, 0, len(meta[obj.(*fyne.Container).Objects[0]]["name"])) // meta is not modified
+
+	buf.Reset()
+	require.Nil(t, ExportGoPreview(obj, meta, &buf))
+	code = buf.String()
+	assert.Contains(t, code, "func newHeaderGUI() *headerGui {")
+	assert.Contains(t, code, `g.title = widget.NewLabel("Header")`)
+	assert.Equal(t, 1, strings.Count(code, "package main"))
+}
+
+func TestInclude_PreviewPackages(t *testing.T) {
+	header := `{
+  "Version": 3,
+  "Object": {
+    "Type": "*fyne.Container",
+    "Layout": "HBox",
+    "Objects": [
+      {"Type": "*widget.Label", "Name": "title", "Binding": "titleText", "Struct": {"Text": "Header"}},
+      {"Type": "*widget.Icon", "Struct": {"Resource": "logo.svg"}}
+    ]
+  }
+}`
+	setupIncludes(t, map[string]string{"header.gui.json": header, "logo.svg": logoSVG})
+
+	obj, meta, err := DecodeObject(strings.NewReader(includeJSON))
+	require.Nil(t, err)
+
+	var buf bytes.Buffer
+	require.Nil(t, ExportGoPreview(obj, meta, &buf))
+	code := buf.String()
+	assert.Contains(t, code, "g.titleText = binding.NewString()")
+	assert.Contains(t, code, `g.loadResource("logo.svg")`)
+
+	file, err := parser.ParseFile(token.NewFileSet(), "main.go", code, parser.ImportsOnly)
+	require.Nil(t, err)
+	var imports []string
+	for _, imp := range file.Imports {
+		imports = append(imports, imp.Path.Value)
+	}
+	assert.Contains(t, imports, `"fyne.io/fyne/v2/data/binding"`)
+	assert.Contains(t, imports, `"fyne.io/fyne/v2/theme"`)
+}
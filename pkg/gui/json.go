@@ -3,11 +3,13 @@ package gui
 import (
 	"encoding/json"
 	"errors"
+	"fmt"
 	"image/color"
 	"io"
-	"log"
 	"net/url"
 	"reflect"
+	"regexp"
+	"sort"
 	"strings"
 	"time"
 
@@ -20,11 +22,18 @@ import (
 	"fyne.io/fyne/v2/widget"
 )
 
+// nodeID matches the IDs of objects, which the properties of a container use to refer to its children.
+var nodeID = regexp.MustCompile(guidefs.NodeIDPattern)
+
 type canvObj struct {
-	Type    string
-	Name    string            `json:",omitempty"`
-	Actions map[string]string `json:",omitempty"`
-	Struct  fyne.CanvasObject `json:",omitempty"`
+	Type         string
+	Name         string            `json:",omitempty"`
+	ID           string            `json:",omitempty"`
+	Binding      string            `json:",omitempty"`
+	Actions      map[string]string `json:",omitempty"`
+	ThemeColors  map[string]string `json:",omitempty"`
+	Translations map[string]string `json:",omitempty"`
+	Struct       interface{}       `json:",omitempty"`
 }
 
 type cntObj struct {
@@ -32,203 +41,401 @@ type cntObj struct {
 	Struct map[string]interface{}
 }
 
+// widgetCont is a registered container widget, the children are restored using its `AddChild` function.
+type widgetCont struct {
+	canvObj
+	Objects []interface{} `json:",omitempty"`
+}
+
 type form struct {
-	Type   string
-	Name   string                 `json:",omitempty"`
-	Struct map[string]interface{} `json:",omitempty"`
+	Type    string
+	Name    string                 `json:",omitempty"`
+	ID      string                 `json:",omitempty"`
+	Actions map[string]string      `json:",omitempty"`
+	Struct  map[string]interface{} `json:",omitempty"`
 }
 
 type formItem struct {
-	HintText, Text string
-	Widget         *canvObj
+	HintText string `json:",omitempty"`
+	Text     string `json:",omitempty"`
+	Widget   interface{}
 }
 
 type cont struct {
 	canvObj
-	Layout     string `json:",omitempty"`
-	Name       string `json:",omitempty"`
-	Objects    []interface{}
+	Layout     string            `json:",omitempty"`
+	Name       string            `json:",omitempty"`
+	ID         string            `json:",omitempty"`
+	Objects    []interface{}     `json:",omitempty"`
 	Properties map[string]string `json:",omitempty"`
 }
 
 // DecodeObject returns a tree of `CanvasObject` elements from the provided JSON `Reader` and
 // updates the metadata map to include any additional information.
+// If any part of the document is invalid a `*DecodeError` listing every problem is returned.
 func DecodeObject(r io.Reader) (fyne.CanvasObject, map[fyne.CanvasObject]map[string]string, error) {
-	guidefs.InitOnce()
-
-	var data interface{}
-	err := json.NewDecoder(r).Decode(&data)
-	if err != nil || data == nil {
+	obj, meta, err := DecodeObjectLenient(r)
+	if err != nil {
 		return nil, nil, err
 	}
 
-	meta := make(map[fyne.CanvasObject]map[string]string)
-	root := data.(map[string]interface{})
+	return obj, meta, nil
+}
+
+// DecodeObjectLenient returns a tree of `CanvasObject` elements from the provided JSON `Reader`
+// like `DecodeObject`, but any invalid values or objects are skipped so that the rest of the document is loaded.
+// If problems were found the partial tree is returned along with a `*DecodeError` that describes them.
+func DecodeObjectLenient(r io.Reader) (fyne.CanvasObject, map[fyne.CanvasObject]map[string]string, error) {
+	guidefs.InitOnce()
 
-	obj, err := DecodeMap(root, meta)
-	return obj, meta, err
+	meta := make(map[fyne.CanvasObject]map[string]string)
+	d := &decoder{meta: meta}
+	obj, err := d.decodeDocument(r)
+	if err != nil {
+		return nil, nil, err
+	}
+	return obj, meta, d.err()
 }
 
 // DecodeMap returns a tree of `CanvasObject` elements from the provided JSON map and
 // updates the metadata map to include any additional information.
+// If any part of the map is invalid a `*DecodeError` listing every problem is returned.
 func DecodeMap(m map[string]interface{}, meta map[fyne.CanvasObject]map[string]string) (fyne.CanvasObject, error) {
 	guidefs.InitOnce()
 
-	switch m["Type"] {
+	d := &decoder{meta: meta}
+	obj := d.decodeMap(m, "")
+	d.checkBindings()
+	if err := d.err(); err != nil {
+		return nil, err
+	}
+	return obj, nil
+}
+
+type decoder struct {
+	meta     map[fyne.CanvasObject]map[string]string
+	problems []DecodeProblem
+	includes []string // the design files being included, to detect cycles
+	bindings []decodedBinding
+
+	paletteDefaults bool // fields missing from a widget keep the values of a new object, see `migrateV2`
+}
+
+// decodedBinding is an object that uses a data binding, with the path of the binding name.
+type decodedBinding struct {
+	obj  fyne.CanvasObject
+	path string
+}
+
+// decodeDocument reads and migrates a JSON document then decodes its root object.
+// An error is returned if the document is not valid, problems with the object are recorded in the decoder.
+func (d *decoder) decodeDocument(r io.Reader) (fyne.CanvasObject, error) {
+	var data interface{}
+	err := json.NewDecoder(r).Decode(&data)
+	if err != nil {
+		return nil, err
+	}
+	m, ok := data.(map[string]interface{})
+	if !ok {
+		return nil, errors.New("document must be a JSON object")
+	}
+
+	d.paletteDefaults = documentVersion(m) < 3
+	doc, err := MigrateDocument(m)
+	if err != nil {
+		return nil, err
+	}
+
+	root, ok := doc["Object"].(map[string]interface{})
+	if !ok {
+		return nil, errors.New("document does not contain an object")
+	}
+
+	obj := d.decodeMap(root, "")
+	d.checkBindings()
+	if obj != nil {
+		if size, ok := d.mapValue(doc["TestSize"], "TestSize"); ok {
+			var s fyne.Size
+			d.decodeFields(reflect.ValueOf(&s).Elem(), size, "TestSize")
+			SetTestSize(obj, d.meta, s)
+		}
+		if dlg, ok := d.mapValue(doc["Dialog"], "Dialog"); ok {
+			d.decodeDialog(obj, dlg)
+		}
+		if win, ok := d.mapValue(doc["Window"], "Window"); ok {
+			d.decodeWindow(obj, win)
+		}
+		if handlers, ok := d.boolValue(doc["Handlers"], "Handlers"); ok {
+			SetUsesHandlers(obj, d.meta, handlers)
+		}
+	}
+	return obj, nil
+}
+
+// checkBindings reports the bindings that the generated code could not declare, and removes them from the objects.
+func (d *decoder) checkBindings() {
+	var invalid []fyne.CanvasObject
+	for _, b := range d.bindings {
+		if err := guidefs.BindingConflict(b.obj, d.meta[b.obj]["binding"], d.meta); err != nil {
+			d.fail(b.path, "%v", err)
+			invalid = append(invalid, b.obj)
+		}
+	}
+
+	for _, obj := range invalid {
+		delete(d.meta[obj], "binding")
+	}
+}
+
+func (d *decoder) err() error {
+	if len(d.problems) == 0 {
+		return nil
+	}
+
+	return &DecodeError{Problems: d.problems}
+}
+
+func (d *decoder) fail(path, format string, args ...interface{}) {
+	d.problems = append(d.problems, DecodeProblem{Path: path, Message: fmt.Sprintf(format, args...)})
+}
+
+func (d *decoder) decodeMap(m map[string]interface{}, path string) fyne.CanvasObject {
+	class, ok := d.stringValue(m["Type"], joinPath(path, "Type"))
+	if !ok {
+		if m["Type"] == nil {
+			d.fail(joinPath(path, "Type"), "missing object type")
+		}
+		return nil
+	}
+	props := map[string]string{}
+	if name, ok := d.stringValue(m["Name"], joinPath(path, "Name")); ok {
+		props["name"] = name
+	}
+	if id, ok := d.stringValue(m["ID"], joinPath(path, "ID")); ok {
+		if nodeID.MatchString(id) {
+			props["id"] = id
+		} else {
+			d.fail(joinPath(path, "ID"), "invalid node ID %q", id)
+		}
+	}
+
+	var obj fyne.CanvasObject
+	switch class {
+	case includeType:
+		src, _ := d.stringValue(m["Src"], joinPath(path, "Src"))
+		inc := d.include(src, joinPath(path, "Src"))
+		d.meta[inc] = props
+		return inc
 	case "*fyne.Container":
-		obj := &fyne.Container{}
-		name := m["Layout"].(string)
+		c := &fyne.Container{}
+		name, ok := d.stringValue(m["Layout"], joinPath(path, "Layout"))
+		if !ok {
+			if m["Layout"] == nil {
+				d.fail(joinPath(path, "Layout"), "missing layout")
+			}
+			return nil
+		}
+		lay, ok := guidefs.Layouts[name]
+		if !ok {
+			d.fail(joinPath(path, "Layout"), "unknown layout %q", name)
+			return nil
+		}
 
-		props := map[string]string{"layout": name}
-		if m["Properties"] != nil {
-			for k, v := range m["Properties"].(map[string]interface{}) {
-				props[k] = v.(string)
+		layoutProps := map[string]string{"layout": name}
+		if set, ok := d.mapValue(m["Properties"], joinPath(path, "Properties")); ok {
+			for _, k := range sortedKeys(set) {
+				propPath := joinPath(joinPath(path, "Properties"), k)
+				if v, ok := d.stringValue(set[k], propPath); ok {
+					if pattern, ok := guidefs.LayoutProperties[name][k]; ok && !regexp.MustCompile(pattern).MatchString(v) {
+						d.fail(propPath, "invalid value %q for the %s layout", v, name)
+						continue
+					}
+					layoutProps[k] = v
+				}
 			}
 		}
 		if name == "HBox" {
-			props["dir"] = "horizontal"
+			layoutProps["dir"] = "horizontal"
 		} else if name == "VBox" {
-			props["dir"] = "vertical"
+			layoutProps["dir"] = "vertical"
 		}
 
-		if m["Objects"] != nil {
-			for _, data := range m["Objects"].([]interface{}) {
+		if objs, ok := d.sliceValue(m["Objects"], joinPath(path, "Objects")); ok {
+			for i, data := range objs {
 				if data == nil {
 					// Nil object?
 					continue
 				}
-				child, _ := DecodeMap(data.(map[string]interface{}), meta)
+				child := d.decodeChild(data, indexPath(joinPath(path, "Objects"), i))
 				if child != nil {
-					obj.Objects = append(obj.Objects, child)
+					c.Objects = append(c.Objects, child)
 				}
 			}
 		}
-		obj.Layout = guidefs.Layouts[name].Create(obj, props)
-		if name, ok := m["Name"]; ok {
-			props["name"] = name.(string)
+		for k, v := range props {
+			layoutProps[k] = v
 		}
-
-		meta[obj] = props
-		return obj, nil
+		d.meta[c] = layoutProps
+		c.Layout = lay.Create(c, d.meta)
+		return c
 	case "*container.AppTabs":
-		obj := &container.AppTabs{}
-		info := m["Struct"].(map[string]interface{})
-
-		items := info["Items"]
-		if items != nil {
-			for _, c := range items.([]interface{}) {
-				item := &container.TabItem{}
+		tabs := &container.AppTabs{}
+		info, ok := d.requiredMap(m, "Struct", path)
+		if !ok {
+			return nil
+		}
 
-				data := c.(map[string]interface{})
-				item.Text = data["Text"].(string)
+		itemsPath := joinPath(joinPath(path, "Struct"), "Items")
+		if items, ok := d.sliceValue(info["Items"], itemsPath); ok {
+			for i, c := range items {
+				itemPath := indexPath(itemsPath, i)
+				data, ok := d.mapValue(c, itemPath)
+				if !ok {
+					continue
+				}
 
-				if data["Icon"] != nil {
-					res := guidefs.Icons[data["Icon"].(string)]
-					if res != nil {
-						item.Icon = res
+				item := &container.TabItem{}
+				item.Text, _ = d.stringValue(data["Text"], joinPath(itemPath, "Text"))
+				if name, ok := d.stringValue(data["Icon"], joinPath(itemPath, "Icon")); ok {
+					item.Icon = d.icon(name, joinPath(itemPath, "Icon"))
+				}
+				item.Content = d.decodeChild(data["Content"], joinPath(itemPath, "Content"))
+				if item.Content == nil {
+					if data["Content"] == nil {
+						d.fail(joinPath(itemPath, "Content"), "missing tab content")
 					}
+					continue
 				}
-				item.Content, _ = DecodeMap(data["Content"].(map[string]interface{}), meta)
-				obj.Append(item)
+				tabs.Append(item)
 			}
 		}
 
-		props := map[string]string{}
-		if name, ok := m["Name"]; ok {
-			props["name"] = name.(string)
-		}
-		if index, ok := info["SelectedIndex"]; ok {
-			obj.SelectIndex(int(index.(float64)))
+		if index, ok := d.numberValue(info["SelectedIndex"], joinPath(joinPath(path, "Struct"), "SelectedIndex")); ok {
+			if int(index) < 0 || int(index) >= len(tabs.Items) {
+				d.fail(joinPath(joinPath(path, "Struct"), "SelectedIndex"), "index %d out of range", int(index))
+			} else {
+				tabs.SelectIndex(int(index))
+			}
 		}
 
-		meta[obj] = props
-		return obj, nil
+		d.meta[tabs] = props
+		return tabs
 	case "*container.Scroll":
-		obj := &container.Scroll{}
-		info := m["Struct"].(map[string]interface{})
-		if off, ok := info["Direction"]; ok {
-			obj.Direction = container.ScrollDirection(off.(float64))
+		scroll := &container.Scroll{}
+		info, ok := d.requiredMap(m, "Struct", path)
+		if !ok {
+			return nil
 		}
-		if info["Content"] != nil {
-			child, _ := DecodeMap(info["Content"].(map[string]interface{}), meta)
-			obj.Content = child
+		if info["Direction"] != nil {
+			d.setValue(reflect.ValueOf(&scroll.Direction).Elem(), info["Direction"], joinPath(joinPath(path, "Struct"), "Direction"))
 		}
-
-		props := map[string]string{}
-		if name, ok := m["Name"]; ok {
-			props["name"] = name.(string)
+		if info["Content"] != nil {
+			scroll.Content = d.decodeChild(info["Content"], joinPath(joinPath(path, "Struct"), "Content"))
 		}
 
-		meta[obj] = props
-		return obj, nil
+		d.meta[scroll] = props
+		return scroll
 	case "*container.Split":
-		obj := &container.Split{}
-		info := m["Struct"].(map[string]interface{})
-		if info["Horizontal"].(bool) {
-			obj.Horizontal = true
+		split := &container.Split{}
+		info, ok := d.requiredMap(m, "Struct", path)
+		if !ok {
+			return nil
 		}
-		if off, ok := info["Offset"]; ok {
-			obj.Offset = off.(float64)
+		if horiz, ok := d.boolValue(info["Horizontal"], joinPath(joinPath(path, "Struct"), "Horizontal")); ok {
+			split.Horizontal = horiz
+		}
+		if off, ok := d.numberValue(info["Offset"], joinPath(joinPath(path, "Struct"), "Offset")); ok {
+			split.Offset = off
 		}
 		if info["Leading"] != nil {
-			child, _ := DecodeMap(info["Leading"].(map[string]interface{}), meta)
-			obj.Leading = child
+			split.Leading = d.decodeChild(info["Leading"], joinPath(joinPath(path, "Struct"), "Leading"))
 		}
 		if info["Trailing"] != nil {
-			child, _ := DecodeMap(info["Trailing"].(map[string]interface{}), meta)
-			obj.Trailing = child
-		}
-
-		props := map[string]string{}
-		if name, ok := m["Name"]; ok {
-			props["name"] = name.(string)
+			split.Trailing = d.decodeChild(info["Trailing"], joinPath(joinPath(path, "Struct"), "Trailing"))
 		}
 
-		meta[obj] = props
-		return obj, nil
+		d.meta[split] = props
+		return split
 	case "*canvas.Rectangle":
-		obj := &canvas.Rectangle{}
-		e := reflect.ValueOf(obj).Elem()
-
-		err := decodeFields(e, m["Struct"].(map[string]interface{}))
-		return obj, err
+		obj = &canvas.Rectangle{}
 	case "*canvas.LinearGradient":
-		obj := &canvas.LinearGradient{}
-		e := reflect.ValueOf(obj).Elem()
-
-		err := decodeFields(e, m["Struct"].(map[string]interface{}))
-		return obj, err
+		obj = &canvas.LinearGradient{}
 	case "*canvas.RadialGradient":
-		obj := &canvas.RadialGradient{}
-		e := reflect.ValueOf(obj).Elem()
+		obj = &canvas.RadialGradient{}
+	}
+	if obj != nil {
+		if info, ok := d.requiredMap(m, "Struct", path); ok {
+			d.decodeFields(reflect.ValueOf(obj).Elem(), info, joinPath(path, "Struct"))
+		}
+		d.decodeThemeColors(obj, m, props, path)
 
-		err := decodeFields(e, m["Struct"].(map[string]interface{}))
-		return obj, err
+		d.meta[obj] = props
+		return obj
 	}
 
-	obj := decodeWidget(m)
+	obj = d.decodeWidget(m, path)
 	if obj == nil {
-		return nil, errors.New("failed to parse object from JSON")
+		return nil
 	}
-	obj.Refresh()
-	props := map[string]string{}
-	if name, ok := m["Name"]; ok {
-		props["name"] = name.(string)
+	if info := guidefs.Lookup(class); info.IsContainer() {
+		if objs, ok := d.sliceValue(m["Objects"], joinPath(path, "Objects")); ok {
+			for i, data := range objs {
+				if child := d.decodeChild(data, indexPath(joinPath(path, "Objects"), i)); child != nil {
+					info.AddChild(obj, child)
+				}
+			}
+		}
 	}
+	obj.Refresh()
 
-	if set, ok := m["Actions"]; ok {
-		if actions, ok := set.(map[string]any); ok {
-			for k, v := range actions {
-				props[k] = v.(string)
+	d.decodeThemeColors(obj, m, props, path)
+	if bind, ok := d.stringValue(m["Binding"], joinPath(path, "Binding")); ok {
+		if guidefs.Bindings[class].Type == "" {
+			d.fail(joinPath(path, "Binding"), "type %s does not support data binding", class)
+		} else {
+			props["binding"] = bind
+			d.bindings = append(d.bindings, decodedBinding{obj: obj, path: joinPath(path, "Binding")})
+		}
+	}
+	if set, ok := d.mapValue(m["Translations"], joinPath(path, "Translations")); ok {
+		for _, k := range sortedKeys(set) {
+			fieldPath := joinPath(joinPath(path, "Translations"), k)
+			if key, ok := d.stringValue(set[k], fieldPath); ok {
+				if !containsString(guidefs.TranslatableFields[class], k) {
+					d.fail(fieldPath, "field %s of %s cannot be translated", k, class)
+					continue
+				}
+				guidefs.SetTranslationKey(props, k, key)
 			}
 		}
 	}
+	if set, ok := d.mapValue(m["Actions"], joinPath(path, "Actions")); ok {
+		for _, k := range sortedKeys(set) {
+			if v, ok := d.stringValue(set[k], joinPath(joinPath(path, "Actions"), k)); ok {
+				props[k] = v
+			}
+		}
+	}
+	if f, ok := obj.(*widget.Form); ok {
+		guidefs.UpdateFormActions(f, props)
+	}
 
-	meta[obj] = props
-	return obj, nil
+	d.meta[obj] = props
+	return obj
+}
+
+// decodeChild decodes a nested object, reporting a problem if the value is not a JSON object.
+func (d *decoder) decodeChild(data interface{}, path string) fyne.CanvasObject {
+	m, ok := d.mapValue(data, path)
+	if !ok {
+		return nil
+	}
+
+	return d.decodeMap(m, path)
 }
 
 // EncodeObject writes a JSON stream for the tree of `CanvasObject` elements provided.
+// The document is written in the current `FormatVersion`.
 // If an error occurs it will be returned, otherwise nil.
 func EncodeObject(obj fyne.CanvasObject, meta map[fyne.CanvasObject]map[string]string, w io.Writer) error {
 	guidefs.InitOnce()
@@ -240,7 +447,14 @@ func EncodeObject(obj fyne.CanvasObject, meta map[fyne.CanvasObject]map[string]s
 
 	e := json.NewEncoder(w)
 	e.SetIndent("", "  ")
-	return e.Encode(tree)
+	doc := &document{Version: FormatVersion, Object: tree}
+	if size, ok := testSize(meta[obj]); ok {
+		doc.TestSize = &size
+	}
+	doc.Dialog = DialogFor(obj, meta)
+	doc.Window = WindowFor(obj, meta)
+	doc.Handlers = UsesHandlers(obj, meta)
+	return e.Encode(doc)
 }
 
 // EncodeMap returns a JSON map for the tree of `CanvasObject` elements provided, using additional metadata if required.
@@ -249,26 +463,16 @@ func EncodeMap(obj fyne.CanvasObject, meta map[fyne.CanvasObject]map[string]stri
 	guidefs.InitOnce()
 
 	props := meta[obj]
-	name := ""
-	actions := map[string]string{}
-	if props == nil {
-		props = make(map[string]string)
-		meta[obj] = props
-	} else {
-		name = props["name"]
-
-		for k, v := range props {
-			if len(k) > 2 && k[0:2] == "On" {
-				actions[k] = v
-			}
-		}
-	}
+	name := props["name"]
 
 	switch c := obj.(type) {
+	case *guidefs.Include:
+		return &includeObj{Type: includeType, Name: name, ID: props["id"], Src: c.Src}, nil
 	case *widget.Accordion:
 		node := &cntObj{Struct: make(map[string]interface{})}
 		node.Type = "*widget.Accordion"
 		node.Name = name
+		node.ID = props["id"]
 
 		items := make([]interface{}, len(c.Items))
 		for i, child := range c.Items {
@@ -280,57 +484,16 @@ func EncodeMap(obj fyne.CanvasObject, meta map[fyne.CanvasObject]map[string]stri
 			items[i] = data
 		}
 		node.Struct["Items"] = items
-		node.Struct["MultiOpen"] = c.MultiOpen
-
-		return &node, nil
-	case *widget.Button:
-		if c.Icon == nil {
-			return encodeWidget(c, name, actions), nil
-		}
-
-		ic := c.Icon
-		c.Icon = guidefs.WrapResource(c.Icon)
-		wid := encodeWidget(c, name, actions)
-		go func() { // TODO find a better way to reset this after encoding
-			time.Sleep(time.Millisecond * 100)
-			c.Icon = ic
-		}()
-		return wid, nil
-	case *widget.Icon:
-		if c.Resource == nil {
-			return encodeWidget(c, name, actions), nil
-		}
-
-		ic := c.Resource
-		c.Resource = guidefs.WrapResource(c.Resource)
-		wid := encodeWidget(c, name, actions)
-		go func() { // TODO find a better way to reset this after encoding
-			time.Sleep(time.Millisecond * 100)
-			c.Resource = ic
-		}()
-		return wid, nil
-	case *widget.Toolbar:
-		for id, i := range c.Items {
-			switch t := i.(type) {
-			case *widget.ToolbarAction:
-				ic := t.Icon
-				t.Icon = guidefs.WrapResource(t.Icon)
-				go func() { // TODO find a better way to reset this after encoding
-					time.Sleep(time.Millisecond * 100)
-					t.Icon = ic
-				}()
-			case *widget.ToolbarSeparator:
-				c.Items[id] = toolbarItem{Type: "Separator"}
-			case *widget.ToolbarSpacer:
-				c.Items[id] = toolbarItem{Type: "Spacer"}
-			}
+		if c.MultiOpen {
+			node.Struct["MultiOpen"] = true
 		}
 
-		return encodeWidget(c, name, actions), nil
+		return &node, nil
 	case *container.AppTabs:
 		node := &cntObj{Struct: make(map[string]interface{})}
 		node.Type = "*container.AppTabs"
 		node.Name = name
+		node.ID = props["id"]
 
 		items := make([]interface{}, len(c.Items))
 		for i, child := range c.Items {
@@ -345,14 +508,19 @@ func EncodeMap(obj fyne.CanvasObject, meta map[fyne.CanvasObject]map[string]stri
 			items[i] = data
 		}
 		node.Struct["Items"] = items
-		node.Struct["SelectedIndex"] = c.SelectedIndex()
+		if index := c.SelectedIndex(); index != 0 {
+			node.Struct["SelectedIndex"] = index
+		}
 
 		return &node, nil
 	case *container.Scroll:
 		node := &cntObj{Struct: make(map[string]interface{})}
 		node.Type = "*container.Scroll"
-		node.Struct["Direction"] = c.Direction
+		if c.Direction != container.ScrollBoth {
+			node.Struct["Direction"] = c.Direction
+		}
 		node.Name = name
+		node.ID = props["id"]
 
 		node.Struct["Content"], _ = EncodeMap(c.Content, meta)
 
@@ -360,9 +528,14 @@ func EncodeMap(obj fyne.CanvasObject, meta map[fyne.CanvasObject]map[string]stri
 	case *container.Split:
 		node := &cntObj{Struct: make(map[string]interface{})}
 		node.Type = "*container.Split"
-		node.Struct["Horizontal"] = c.Horizontal
-		node.Struct["Offset"] = c.Offset
+		if c.Horizontal {
+			node.Struct["Horizontal"] = true
+		}
+		if c.Offset != 0 {
+			node.Struct["Offset"] = c.Offset
+		}
 		node.Name = name
+		node.ID = props["id"]
 
 		node.Struct["Leading"], _ = EncodeMap(c.Leading, meta)
 		node.Struct["Trailing"], _ = EncodeMap(c.Trailing, meta)
@@ -370,15 +543,24 @@ func EncodeMap(obj fyne.CanvasObject, meta map[fyne.CanvasObject]map[string]stri
 		return &node, nil
 	case fyne.Widget:
 		if form, ok := c.(*widget.Form); ok {
-			return encodeForm(form, name), nil
+			return encodeForm(form, meta), nil
+		}
+		if info := guidefs.Lookup(reflect.TypeOf(c).String()); info != nil && info.IsContainer() {
+			node := &widgetCont{canvObj: *encodeWidget(c, props)}
+			for _, o := range info.Children(c) {
+				enc, _ := EncodeMap(o, meta)
+				node.Objects = append(node.Objects, enc)
+			}
+			return node, nil
 		}
-		return encodeWidget(c, name, actions), nil
+		return encodeWidget(c, props), nil
 	case *fyne.Container:
 		var node cont
 		node.Type = "*fyne.Container"
 		node.Layout = strings.Split(reflect.TypeOf(c.Layout).String(), ".")[1]
 		node.Layout = strings.ToTitle(node.Layout[0:1]) + node.Layout[1:]
 		node.Name = name
+		node.ID = props["id"]
 		p := strings.Index(node.Layout, "Layout")
 		if p > 0 {
 			node.Layout = node.Layout[:p]
@@ -390,84 +572,169 @@ func EncodeMap(obj fyne.CanvasObject, meta map[fyne.CanvasObject]map[string]stri
 				node.Layout = "VBox"
 			}
 		}
+		if _, ok := guidefs.CustomLayouts[props["layout"]]; ok {
+			node.Layout = props["layout"] // the type of a registered layout may not match its name
+		}
 		for _, o := range c.Objects {
 			enc, _ := EncodeMap(o, meta)
 			node.Objects = append(node.Objects, enc)
 		}
-		node.Properties = meta[c]
+		node.Properties = layoutProperties(node.Layout, meta[c])
 		return &node, nil
 	}
 
-	return &canvObj{Type: reflect.TypeOf(obj).String(), Name: name, Struct: obj}, nil
+	return encodeWidget(obj, props), nil
 }
 
-func encodeForm(obj *widget.Form, name string) interface{} {
+func encodeForm(obj *widget.Form, meta map[fyne.CanvasObject]map[string]string) interface{} {
 	var items []*formItem
 	for _, o := range obj.Items {
-		items = append(items,
-			&formItem{
-				HintText: o.HintText,
-				Text:     o.Text,
-				Widget:   encodeWidget(o.Widget, "", nil),
-			})
+		item := &formItem{HintText: o.HintText, Text: o.Text}
+		item.Widget, _ = EncodeMap(o.Widget, meta)
+		items = append(items, item)
 	}
 
 	var node form
 	node.Type = "*widget.Form"
-	node.Name = name
-	node.Struct = map[string]interface{}{
-		"Hidden":     obj.Hidden,
-		"Items":      items,
-		"SubmitText": obj.SubmitText,
-		"CancelText": obj.CancelText,
+	node.Name = meta[obj]["name"]
+	node.ID = meta[obj]["id"]
+	node.Actions = encodeActions(meta[obj])
+	node.Struct = map[string]interface{}{"Items": items}
+	if obj.Hidden {
+		node.Struct["Hidden"] = true
+	}
+	if obj.SubmitText != "" {
+		node.Struct["SubmitText"] = obj.SubmitText
+	}
+	if obj.CancelText != "" {
+		node.Struct["CancelText"] = obj.CancelText
 	}
 
 	return &node
 }
 
-func encodeWidget(obj fyne.CanvasObject, name string, actions map[string]string) *canvObj {
-	w := &canvObj{Type: reflect.TypeOf(obj).String(), Name: name, Struct: obj}
+func encodeWidget(obj fyne.CanvasObject, props map[string]string) *canvObj {
+	colors := guidefs.ThemeColors(obj, props)
+	w := &canvObj{Type: reflect.TypeOf(obj).String(), Name: props["name"], ID: props["id"], Binding: props["binding"],
+		Struct: &objectStruct{obj, reflect.New(reflect.TypeOf(obj).Elem()).Interface(), colors}}
+	w.Actions = encodeActions(props)
+	w.ThemeColors = colors
+	w.Translations = guidefs.Translations(w.Type, props)
+
+	return w
+}
 
-	if len(actions) > 0 {
-		w.Actions = actions
+// layoutProperties returns the metadata of a container that is not stored elsewhere in its node, or nil if there is none.
+// The layout, name, node ID and box direction are restored from the node and document keys are stored in the document.
+func layoutProperties(layout string, props map[string]string) map[string]string {
+	var ret map[string]string
+	for k, v := range props {
+		if k == "layout" || k == "name" || k == "id" || isDocumentKey(k) || (k == "dir" && (layout == "HBox" || layout == "VBox")) {
+			continue
+		}
+
+		if ret == nil {
+			ret = make(map[string]string)
+		}
+		ret[k] = v
 	}
+	return ret
+}
 
-	return w
+// encodeActions returns the actions set in the properties of an object, or nil if there are none.
+func encodeActions(props map[string]string) map[string]string {
+	actions := map[string]string{}
+	for k, v := range props {
+		if len(k) > 2 && k[0:2] == "On" {
+			actions[k] = v
+		}
+	}
+	if len(actions) == 0 {
+		return nil
+	}
+	return actions
 }
 
-func decodeAccordionItem(m map[string]interface{}) *widget.AccordionItem {
+// decodeThemeColors stores the theme color names of an object in its metadata and applies the current theme value.
+func (d *decoder) decodeThemeColors(obj fyne.CanvasObject, m map[string]interface{}, props map[string]string, path string) {
+	colors, ok := d.mapValue(m["ThemeColors"], joinPath(path, "ThemeColors"))
+	if !ok {
+		return
+	}
+
+	fields := guidefs.ColorFields(obj)
+	val := reflect.ValueOf(obj).Elem()
+	for _, k := range sortedKeys(colors) {
+		fieldPath := joinPath(joinPath(path, "ThemeColors"), k)
+		name, ok := d.stringValue(colors[k], fieldPath)
+		if !ok {
+			continue
+		}
+
+		found := false
+		for _, f := range fields {
+			if f == k {
+				found = true
+				break
+			}
+		}
+		if !found {
+			d.fail(fieldPath, "%s is not a color field", k)
+			continue
+		}
+
+		props[k] = name
+		val.FieldByName(k).Set(reflect.ValueOf(guidefs.ThemeColor(name)))
+	}
+}
+
+func (d *decoder) decodeAccordionItem(m map[string]interface{}, path string) *widget.AccordionItem {
 	f := &widget.AccordionItem{}
-	if str, ok := m["Title"]; ok {
-		f.Title = str.(string)
+	if str, ok := d.stringValue(m["Title"], joinPath(path, "Title")); ok {
+		f.Title = str
+	}
+	if on, ok := d.boolValue(m["Open"], joinPath(path, "Open")); ok {
+		f.Open = on
 	}
-	if on, ok := m["Open"]; ok {
-		f.Open = on.(bool)
+	wid, ok := d.mapValue(m["Detail"], joinPath(path, "Detail"))
+	if !ok {
+		if m["Detail"] == nil {
+			d.fail(joinPath(path, "Detail"), "missing value")
+		}
+		return nil
 	}
-	if wid, ok := m["Detail"]; ok {
-		f.Detail = decodeWidget(wid.(map[string]interface{}))
+	f.Detail = d.decodeWidget(wid, joinPath(path, "Detail"))
+	if f.Detail == nil {
+		return nil
 	}
 	return f
 }
 
-func decodeFormItem(m map[string]interface{}) *widget.FormItem {
+func (d *decoder) decodeFormItem(m map[string]interface{}, path string) *widget.FormItem {
 	f := &widget.FormItem{}
-	if str, ok := m["HintText"]; ok {
-		f.HintText = str.(string)
+	if str, ok := d.stringValue(m["HintText"], joinPath(path, "HintText")); ok {
+		f.HintText = str
 	}
-	if str, ok := m["Text"]; ok {
-		f.Text = str.(string)
+	if str, ok := d.stringValue(m["Text"], joinPath(path, "Text")); ok {
+		f.Text = str
 	}
-	if wid, ok := m["Widget"]; ok {
-		f.Widget = decodeWidget(wid.(map[string]interface{}))
+	if m["Widget"] == nil {
+		d.fail(joinPath(path, "Widget"), "missing value")
+		return nil
+	}
+	f.Widget = d.decodeChild(m["Widget"], joinPath(path, "Widget"))
+	if f.Widget == nil {
+		return nil
 	}
 	return f
 }
 
-func decodeFromMap(m map[string]interface{}, in interface{}) {
+func (d *decoder) decodeFromMap(m map[string]interface{}, in interface{}, path string) {
 	t := reflect.ValueOf(in).Elem()
-	for k, v := range m {
+	for _, k := range sortedKeys(m) {
+		v := m[k]
 		val := t.FieldByName(k)
-		if !val.IsValid() {
+		if !val.IsValid() || v == nil {
 			continue
 		}
 
@@ -475,38 +742,44 @@ func decodeFromMap(m map[string]interface{}, in interface{}) {
 		case reflect.Ptr:
 			continue
 		case reflect.Uint8:
-			val.SetUint(uint64(reflect.ValueOf(v).Float()))
+			if f, ok := d.numberValue(v, joinPath(path, k)); ok {
+				if f < 0 || f > 255 {
+					d.fail(joinPath(path, k), "value %v out of range", f)
+					continue
+				}
+				val.SetUint(uint64(f))
+			}
 		default:
-			val.Set(reflect.ValueOf(v))
+			d.setValue(val, v, joinPath(path, k))
 		}
 	}
 }
 
-func decodeTextStyle(m map[string]interface{}) (s fyne.TextStyle) {
-	if m["Bold"] == true {
-		s.Bold = true
-	}
-	if m["Italic"] == true {
-		s.Italic = true
-	}
-	if m["Monospace"] == true {
-		s.Monospace = true
-	}
-
-	if m["TabWidth"] != 0 {
-		s.TabWidth = int(m["TabWidth"].(float64))
+func (d *decoder) decodeTextStyle(m map[string]interface{}, path string) (s fyne.TextStyle) {
+	s.Bold, _ = d.boolValue(m["Bold"], joinPath(path, "Bold"))
+	s.Italic, _ = d.boolValue(m["Italic"], joinPath(path, "Italic"))
+	s.Monospace, _ = d.boolValue(m["Monospace"], joinPath(path, "Monospace"))
+	s.Symbol, _ = d.boolValue(m["Symbol"], joinPath(path, "Symbol"))
+	s.Underline, _ = d.boolValue(m["Underline"], joinPath(path, "Underline"))
+
+	if tab, ok := d.numberValue(m["TabWidth"], joinPath(path, "TabWidth")); ok {
+		if tab < 0 {
+			d.fail(joinPath(path, "TabWidth"), "invalid tab width %v", tab)
+		} else {
+			s.TabWidth = int(tab)
+		}
 	}
 	return
 }
 
-func decodePosition(m map[string]interface{}) fyne.Position {
-	x := m["X"].(float64)
-	y := m["Y"].(float64)
+func (d *decoder) decodePosition(m map[string]interface{}, path string) fyne.Position {
+	x, _ := d.numberValue(m["X"], joinPath(path, "X"))
+	y, _ := d.numberValue(m["Y"], joinPath(path, "Y"))
 
 	return fyne.NewPos(float32(x), float32(y))
 }
 
-func decodeToolbarItem(m map[string]interface{}) widget.ToolbarItem {
+func (d *decoder) decodeToolbarItem(m map[string]interface{}, path string) widget.ToolbarItem {
 	if v, ok := m["Type"]; ok {
 		switch v {
 		case "Separator":
@@ -516,154 +789,366 @@ func decodeToolbarItem(m map[string]interface{}) widget.ToolbarItem {
 		}
 	}
 
-	return widget.NewToolbarAction(guidefs.Icons[m["Icon"].(string)], nil)
-}
-
-func decodeRichTextStyle(m map[string]interface{}) (s widget.RichTextStyle) {
-	for k, v := range m {
-		switch k {
-		case "TextStyle":
-			s.TextStyle = decodeTextStyle(v.(map[string]interface{}))
-			// TODO more!
+	name, ok := d.stringValue(m["Icon"], joinPath(path, "Icon"))
+	if !ok {
+		if m["Icon"] == nil {
+			d.fail(joinPath(path, "Icon"), "missing toolbar icon")
 		}
+		return nil
 	}
+	return widget.NewToolbarAction(d.icon(name, joinPath(path, "Icon")), nil)
+}
 
+func (d *decoder) decodeRichTextStyle(m map[string]interface{}, path string) (s widget.RichTextStyle) {
+	d.decodeFields(reflect.ValueOf(&s).Elem(), m, path)
 	return
 }
 
-func decodeFields(e reflect.Value, in map[string]interface{}) error {
-	for k, v := range in {
+func (d *decoder) decodeFields(e reflect.Value, in map[string]interface{}, path string) {
+	for _, k := range sortedKeys(in) {
+		v := in[k]
 		f := e.FieldByName(k)
+		fieldPath := joinPath(path, k)
 
-		if !f.IsValid() {
-			fyne.LogError("Field "+k+" is not valid", nil)
+		field, _ := e.Type().FieldByName(k)
+		if !f.IsValid() || !f.CanSet() || field.Anonymous { // embedded types are not stored
+			d.fail(fieldPath, "unknown field %s", k)
+			continue
+		}
+		if v == nil {
+			f.Set(reflect.Zero(f.Type())) // fields that match the defaults are left out, so null is a value
 			continue
 		}
 
 		typeName := f.Type().String()
 		switch typeName {
-		case "fyne.TextAlign", "fyne.TextTruncation", "fyne.TextWrap", "widget.ButtonAlign", "widget.ButtonImportance",
-			"widget.ButtonIconPlacement", "widget.Importance", "widget.Orientation", "widget.ScrollDirection", "fyne.ScrollDirection":
-			f.SetInt(int64(reflect.ValueOf(v).Float()))
 		case "fyne.TextStyle":
-			f.Set(reflect.ValueOf(decodeTextStyle(reflect.ValueOf(v).Interface().(map[string]interface{}))))
+			if m, ok := d.mapValue(v, fieldPath); ok {
+				f.Set(reflect.ValueOf(d.decodeTextStyle(m, fieldPath)))
+			}
 		case "widget.RichTextStyle":
-			f.Set(reflect.ValueOf(decodeRichTextStyle(reflect.ValueOf(v).Interface().(map[string]interface{}))))
+			if m, ok := d.mapValue(v, fieldPath); ok {
+				f.Set(reflect.ValueOf(d.decodeRichTextStyle(m, fieldPath)))
+			}
 		case "fyne.Position":
-			f.Set(reflect.ValueOf(decodePosition(reflect.ValueOf(v).Interface().(map[string]interface{}))))
-		case "fyne.Resource":
-			res := guidefs.Icons[reflect.ValueOf(v).String()]
-			if res != nil {
-				f.Set(reflect.ValueOf(res))
+			if m, ok := d.mapValue(v, fieldPath); ok {
+				f.Set(reflect.ValueOf(d.decodePosition(m, fieldPath)))
 			}
-		case "fyne.ThemeSizeName":
-			if v != nil {
-				f.Set(reflect.ValueOf(fyne.ThemeSizeName(v.(string))))
+		case "fyne.Resource":
+			if name, ok := d.stringValue(v, fieldPath); ok {
+				if res := d.icon(name, fieldPath); res != nil {
+					f.Set(reflect.ValueOf(res))
+				}
 			}
 		case "[]*widget.AccordionItem":
 			var items []*widget.AccordionItem
-			for _, item := range reflect.ValueOf(v).Interface().([]interface{}) {
-				items = append(items, decodeAccordionItem(item.(map[string]interface{})))
+			list, _ := d.sliceValue(v, fieldPath)
+			for i, item := range list {
+				if m, ok := d.mapValue(item, indexPath(fieldPath, i)); ok {
+					if accItem := d.decodeAccordionItem(m, indexPath(fieldPath, i)); accItem != nil {
+						items = append(items, accItem)
+					}
+				}
 			}
 			f.Set(reflect.ValueOf(items))
 		case "[]*widget.FormItem":
 			var items []*widget.FormItem
-			for _, item := range reflect.ValueOf(v).Interface().([]interface{}) {
-				items = append(items, decodeFormItem(item.(map[string]interface{})))
+			list, _ := d.sliceValue(v, fieldPath)
+			for i, item := range list {
+				if m, ok := d.mapValue(item, indexPath(fieldPath, i)); ok {
+					if formItem := d.decodeFormItem(m, indexPath(fieldPath, i)); formItem != nil {
+						items = append(items, formItem)
+					}
+				}
 			}
 			f.Set(reflect.ValueOf(items))
 		case "[]widget.ToolbarItem":
 			var items []widget.ToolbarItem
-			for _, item := range reflect.ValueOf(v).Interface().([]interface{}) {
-				items = append(items, decodeToolbarItem(item.(map[string]interface{})))
+			list, _ := d.sliceValue(v, fieldPath)
+			for i, item := range list {
+				if m, ok := d.mapValue(item, indexPath(fieldPath, i)); ok {
+					if tool := d.decodeToolbarItem(m, indexPath(fieldPath, i)); tool != nil {
+						items = append(items, tool)
+					}
+				}
 			}
 			f.Set(reflect.ValueOf(items))
 		case "[]widget.RichTextSegment":
 			var items []widget.RichTextSegment
-			for _, item := range reflect.ValueOf(v).Interface().([]interface{}) {
-				obj := &widget.TextSegment{}
-				_ = decodeFields(reflect.ValueOf(obj).Elem(), item.(map[string]interface{}))
-				items = append(items, obj)
+			list, _ := d.sliceValue(v, fieldPath)
+			for i, item := range list {
+				if m, ok := d.mapValue(item, indexPath(fieldPath, i)); ok {
+					obj := &widget.TextSegment{}
+					d.decodeFields(reflect.ValueOf(obj).Elem(), m, indexPath(fieldPath, i))
+					items = append(items, obj)
+				}
 			}
 			f.Set(reflect.ValueOf(items))
 		case "fyne.CanvasObject":
-			return errors.New("unsupported object type")
+			continue // nested content is not stored in the widget struct
 		case "*url.URL":
-			u := &url.URL{}
-			decodeFromMap(reflect.ValueOf(v).Interface().(map[string]interface{}), u)
-			f.Set(reflect.ValueOf(u))
+			if m, ok := d.mapValue(v, fieldPath); ok {
+				u := &url.URL{}
+				d.decodeFromMap(m, u, fieldPath)
+				f.Set(reflect.ValueOf(u))
+			}
 		case "[]string":
-			anySlice := reflect.ValueOf(v).Interface().([]interface{})
-			strings := make([]string, len(anySlice))
-			for i, a := range anySlice {
-				strings[i] = a.(string)
+			list, _ := d.sliceValue(v, fieldPath)
+			strings := make([]string, 0, len(list))
+			for i, a := range list {
+				if s, ok := d.stringValue(a, indexPath(fieldPath, i)); ok {
+					strings = append(strings, s)
+				}
 			}
 			f.Set(reflect.ValueOf(strings))
-		case "time.Time":
-			s := reflect.ValueOf(v).String()
-
-			t, err := time.Parse(time.RFC3339, s)
-			if err != nil {
-				fyne.LogError("Failed parse time "+s, err)
-			} else {
-				f.Set(reflect.ValueOf(t))
+		case "time.Time", "*time.Time":
+			s, ok := d.stringValue(v, fieldPath)
+			if !ok {
+				continue
 			}
-		case "*time.Time":
-			s := reflect.ValueOf(v).String()
 
 			t, err := time.Parse(time.RFC3339, s)
 			if err != nil {
-				fyne.LogError("Failed parse time "+s, err)
+				d.fail(fieldPath, "invalid time %q", s)
+			} else if typeName == "time.Time" {
+				f.Set(reflect.ValueOf(t))
 			} else {
 				f.Set(reflect.ValueOf(&t))
 			}
 		case "color.Color":
-			c := &color.NRGBA{}
-			val := reflect.ValueOf(v)
-			if v == nil {
-				continue
-			}
+			if m, ok := d.mapValue(v, fieldPath); ok {
+				if _, gray := m["Y"]; gray { // color.White and color.Black are stored as gray values
+					c := &color.Gray16{}
+					d.decodeFromMap(m, c, fieldPath)
+					f.Set(reflect.ValueOf(c))
+					continue
+				}
 
-			decodeFromMap(val.Interface().(map[string]interface{}), c)
-			f.Set(reflect.ValueOf(c))
-		default:
-			if strings.Index(typeName, "int") == 0 {
-				f.SetInt(int64(reflect.ValueOf(v).Float()))
-			} else if typeName == "float32" {
-				f.SetFloat(reflect.ValueOf(v).Float())
-			} else if v != nil {
-				f.Set(reflect.ValueOf(v))
+				c := &color.NRGBA{}
+				d.decodeFromMap(m, c, fieldPath)
+				f.Set(reflect.ValueOf(c))
 			}
+		default:
+			d.setValue(f, v, fieldPath)
 		}
 	}
+}
 
-	return nil
+// decodedTypes are the types of fields, other than basic values, that `decodeFields` restores from a design.
+var decodedTypes = []string{"fyne.TextStyle", "widget.RichTextStyle", "fyne.Position", "fyne.Resource",
+	"[]*widget.AccordionItem", "[]*widget.FormItem", "[]widget.ToolbarItem", "[]widget.RichTextSegment", "*url.URL",
+	"[]string", "time.Time", "*time.Time", "color.Color"}
+
+// isDecodedType returns true if `decodeFields` sets fields of the type from a design, other fields are not stored.
+func isDecodedType(t reflect.Type) bool {
+	switch t.Kind() {
+	case reflect.Bool, reflect.String, reflect.Float32, reflect.Float64,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return true
+	}
+	return containsString(decodedTypes, t.String())
+}
+
+// enumValues maps the enumeration types of widget fields to the number of values they have.
+// Other values are rejected, as widgets may index tables with them.
+var enumValues = map[string]int{
+	"fyne.TextAlign":             3,
+	"fyne.TextTruncation":        3,
+	"fyne.TextWrap":              4,
+	"widget.ButtonAlign":         3,
+	"widget.ButtonIconPlacement": 2,
+	"widget.Importance":          6,
+	"widget.Orientation":         3,
+	"widget.ScrollDirection":     4,
+}
+
+// setValue sets a basic field from a JSON value. Values of other types are not stored in this format and are ignored.
+func (d *decoder) setValue(f reflect.Value, v interface{}, path string) {
+	switch f.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		num, ok := d.numberValue(v, path)
+		if !ok {
+			return
+		}
+		if count, enum := enumValues[f.Type().String()]; enum && (num < 0 || num >= float64(count) || num != float64(int(num))) {
+			d.fail(path, "invalid %s value %v", f.Type().String(), num)
+			return
+		}
+		f.SetInt(int64(num))
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		if num, ok := d.numberValue(v, path); ok {
+			f.SetUint(uint64(num))
+		}
+	case reflect.Float32, reflect.Float64:
+		if num, ok := d.numberValue(v, path); ok {
+			f.SetFloat(num)
+		}
+	case reflect.Bool:
+		if b, ok := d.boolValue(v, path); ok {
+			f.SetBool(b)
+		}
+	case reflect.String:
+		if s, ok := d.stringValue(v, path); ok {
+			f.SetString(s)
+		}
+	default:
+		if val := reflect.ValueOf(v); val.Type().AssignableTo(f.Type()) {
+			f.Set(val)
+		}
+	}
 }
 
-func decodeWidget(m map[string]interface{}) fyne.CanvasObject {
-	class, ok := m["Type"].(string)
+func (d *decoder) decodeWidget(m map[string]interface{}, path string) fyne.CanvasObject {
+	class, ok := d.stringValue(m["Type"], joinPath(path, "Type"))
 	if !ok {
-		log.Println("Failed to detect type of object")
+		if m["Type"] == nil {
+			d.fail(joinPath(path, "Type"), "missing object type")
+		}
+		return nil
+	}
+	info := guidefs.Lookup(class)
+	if info == nil {
+		d.fail(joinPath(path, "Type"), "unknown type %q", class)
 		return nil
 	}
-	obj := guidefs.Lookup(class).Create()
+	obj := info.Create()
 	e := reflect.ValueOf(obj).Elem()
+	if !d.paletteDefaults {
+		clearStoredFields(e)
+	}
 
-	data, ok := m["Struct"]
+	data, ok := d.requiredMap(m, "Struct", path)
 	if !ok {
-		fyne.LogError("Struct was not found in JSON data", nil)
 		return obj
 	}
 
-	err := decodeFields(e, data.(map[string]interface{}))
-	if err != nil {
-		fyne.LogError("Failed to handle type "+class, err)
-	}
+	d.decodeFields(e, data, joinPath(path, "Struct"))
 	return obj
 }
 
+func (d *decoder) icon(name, path string) fyne.Resource {
+	if guidefs.IsProjectResource(name) {
+		res, err := guidefs.LoadProjectResource(name)
+		if err != nil {
+			d.fail(path, "failed to load resource %q: %v", name, err)
+			return nil
+		}
+		return res
+	}
+
+	res := guidefs.Icons[name]
+	if res == nil {
+		d.fail(path, "unknown icon %q", name)
+	}
+	return res
+}
+
+// requiredMap returns the JSON object at the key in m, reporting a problem if it is missing or invalid.
+func (d *decoder) requiredMap(m map[string]interface{}, key, path string) (map[string]interface{}, bool) {
+	if m[key] == nil {
+		d.fail(joinPath(path, key), "missing value")
+		return nil, false
+	}
+
+	return d.mapValue(m[key], joinPath(path, key))
+}
+
+// The value helpers return false if the value is missing, reporting a problem only if it had the wrong type.
+
+func (d *decoder) boolValue(v interface{}, path string) (bool, bool) {
+	if v == nil {
+		return false, false
+	}
+	b, ok := v.(bool)
+	if !ok {
+		d.fail(path, "expected a boolean, found %s", jsonType(v))
+	}
+	return b, ok
+}
+
+func (d *decoder) mapValue(v interface{}, path string) (map[string]interface{}, bool) {
+	if v == nil {
+		return nil, false
+	}
+	m, ok := v.(map[string]interface{})
+	if !ok {
+		d.fail(path, "expected an object, found %s", jsonType(v))
+	}
+	return m, ok
+}
+
+func (d *decoder) numberValue(v interface{}, path string) (float64, bool) {
+	if v == nil {
+		return 0, false
+	}
+	f, ok := v.(float64)
+	if !ok {
+		d.fail(path, "expected a number, found %s", jsonType(v))
+	}
+	return f, ok
+}
+
+func (d *decoder) sliceValue(v interface{}, path string) ([]interface{}, bool) {
+	if v == nil {
+		return nil, false
+	}
+	s, ok := v.([]interface{})
+	if !ok {
+		d.fail(path, "expected an array, found %s", jsonType(v))
+	}
+	return s, ok
+}
+
+func (d *decoder) stringValue(v interface{}, path string) (string, bool) {
+	if v == nil {
+		return "", false
+	}
+	s, ok := v.(string)
+	if !ok {
+		d.fail(path, "expected a string, found %s", jsonType(v))
+	}
+	return s, ok
+}
+
+func jsonType(v interface{}) string {
+	switch v.(type) {
+	case bool:
+		return "boolean"
+	case float64:
+		return "number"
+	case string:
+		return "string"
+	case []interface{}:
+		return "array"
+	case map[string]interface{}:
+		return "object"
+	}
+
+	return "null"
+}
+
+func joinPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+
+	return path + "." + key
+}
+
+func indexPath(path string, i int) string {
+	return fmt.Sprintf("%s[%d]", path, i)
+}
+
+func sortedKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
 type toolbarItem struct {
 	Type string
 }
-
-func (toolbarItem) ToolbarObject() fyne.CanvasObject { return nil }
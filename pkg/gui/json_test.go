@@ -4,11 +4,13 @@ import (
 	"bytes"
 	"fmt"
 	"strings"
+	"sync"
 	"testing"
 
 	"fyne.io/fyne/v2"
 	"fyne.io/fyne/v2/container"
-	_ "fyne.io/fyne/v2/test"
+	"fyne.io/fyne/v2/test"
+	"fyne.io/fyne/v2/theme"
 	"fyne.io/fyne/v2/widget"
 
 	"github.com/fyne-io/defyne/internal/guidefs"
@@ -36,8 +38,23 @@ const labelJSON = `{
   }
 }`
 
+// canonicalLabelJSON is labelJSON as it is encoded, without the fields that match a new label.
+const canonicalLabelJSON = `{
+  "Type": "*widget.Label",%s
+  "Struct": {
+    "Text": "Hi",
+    "Alignment": 1,
+    "TextStyle": {
+      "Bold": true
+    }
+  }
+}`
+
 func labelJSONWith(indent string) string {
-	in := fmt.Sprintf(labelJSON, "")
+	return indentJSON(fmt.Sprintf(labelJSON, ""), indent)
+}
+
+func indentJSON(in, indent string) string {
 	out := ""
 
 	rows := strings.Split(in, "\n")
@@ -65,8 +82,26 @@ var splitJSON = `{
     "Offset": 0.75,
     "Trailing": ` + labelJSONWith("    ") + `
   }
+}`
+
+var canonicalSplitJSON = `{
+  "Type": "*container.Split",
+  "Name": "mySplit",
+  "Struct": {
+    "Horizontal": true,
+    "Leading": ` + indentJSON(fmt.Sprintf(canonicalLabelJSON, ""), "    ") + `,
+    "Offset": 0.75,
+    "Trailing": ` + indentJSON(fmt.Sprintf(canonicalLabelJSON, ""), "    ") + `
+  }
+}`
+
+func documentJSON(obj string) string {
+	return fmt.Sprintf(`{
+  "Version": %d,
+  "Object": %s
+}
+`, FormatVersion, indentJSON(obj, "  "))
 }
-`
 
 func TestDecodeObject(t *testing.T) {
 	guidefs.InitOnce()
@@ -115,7 +150,46 @@ func TestEncodeObject(t *testing.T) {
 	var buf bytes.Buffer
 	err := EncodeObject(l, meta, &buf)
 	assert.Nil(t, err)
-	assert.Equal(t, fmt.Sprintf(labelJSON, "\n  \"Name\": \"myLabel\",")+"\n", buf.String())
+	assert.Equal(t, documentJSON(fmt.Sprintf(canonicalLabelJSON, "\n  \"Name\": \"myLabel\",")), buf.String())
+}
+
+func TestEncodeObject_Concurrent(t *testing.T) {
+	b := widget.NewButtonWithIcon("Save", theme.DocumentSaveIcon(), nil)
+	i := widget.NewIcon(theme.HomeIcon())
+	tb := widget.NewToolbar(widget.NewToolbarAction(theme.ContentCutIcon(), nil),
+		widget.NewToolbarSeparator(), widget.NewToolbarSpacer())
+	c := container.NewVBox(b, i, tb)
+	meta := map[fyne.CanvasObject]map[string]string{c: {"layout": "VBox", "dir": "vertical"}}
+
+	w := test.NewWindow(c)
+	defer w.Close()
+
+	var wg sync.WaitGroup
+	outputs := make([]string, 4)
+	for n := range outputs {
+		wg.Add(1)
+		go func(n int) {
+			defer wg.Done()
+			var buf bytes.Buffer
+			assert.Nil(t, EncodeObject(c, meta, &buf))
+			outputs[n] = buf.String()
+		}(n)
+	}
+	for n := 0; n < 4; n++ {
+		w.Canvas().Capture()
+	}
+	wg.Wait()
+
+	for _, out := range outputs[1:] {
+		assert.Equal(t, outputs[0], out)
+	}
+	assert.Contains(t, outputs[0], `"Icon": "DocumentSaveIcon"`)
+	assert.Contains(t, outputs[0], `"Type": "Separator"`)
+	assert.Equal(t, theme.DocumentSaveIcon(), b.Icon)
+	assert.Equal(t, theme.HomeIcon(), i.Resource)
+	assert.IsType(t, &widget.ToolbarSeparator{}, tb.Items[1])
+	assert.IsType(t, &widget.ToolbarSpacer{}, tb.Items[2])
+	assert.Equal(t, 1, len(meta))
 }
 
 func TestEncodeSplit(t *testing.T) {
@@ -131,5 +205,45 @@ func TestEncodeSplit(t *testing.T) {
 	var buf bytes.Buffer
 	err := EncodeObject(s, meta, &buf)
 	assert.Nil(t, err)
-	assert.Equal(t, splitJSON, buf.String())
+	assert.Equal(t, documentJSON(canonicalSplitJSON), buf.String())
+}
+
+func TestEncodeObject_Minimal(t *testing.T) {
+	b := widget.NewButton("", nil)
+	i := widget.NewIcon(nil)
+	c := container.NewHBox(b, i)
+	meta := map[fyne.CanvasObject]map[string]string{c: {"layout": "HBox", "dir": "horizontal", "name": "row"}}
+
+	var buf bytes.Buffer
+	require.Nil(t, EncodeObject(c, meta, &buf))
+	assert.NotContains(t, buf.String(), "Hidden")
+	assert.NotContains(t, buf.String(), "Properties")
+	assert.NotContains(t, buf.String(), `"Text"`)
+	assert.NotContains(t, buf.String(), `"Resource"`)
+	assert.Equal(t, 2, strings.Count(buf.String(), `"Struct": {}`))
+
+	obj, meta2, err := DecodeObject(&buf)
+	require.Nil(t, err)
+	objs := obj.(*fyne.Container).Objects
+	assert.Equal(t, "", objs[0].(*widget.Button).Text)
+	assert.Nil(t, objs[1].(*widget.Icon).Resource)
+	assert.Equal(t, map[string]string{"layout": "HBox", "dir": "horizontal", "name": "row"}, meta2[obj])
+}
+
+func TestEncodeObject_MinimalPaletteDefaults(t *testing.T) {
+	l := CreateNew("*widget.Label").(*widget.Label)
+	require.NotEqual(t, "", l.Text)
+	l.SetText("")
+
+	var buf bytes.Buffer
+	require.Nil(t, EncodeObject(l, nil, &buf))
+	assert.NotContains(t, buf.String(), `"Text"`)
+	obj, _, err := DecodeObject(&buf)
+	require.Nil(t, err)
+	assert.Equal(t, "", obj.(*widget.Label).Text)
+
+	// version 2 documents left out the values of a new object from the palette
+	obj, _, err = DecodeObject(strings.NewReader(`{"Version": 2, "Object": {"Type": "*widget.Label", "Struct": {}}}`))
+	require.Nil(t, err)
+	assert.Equal(t, CreateNew("*widget.Label").(*widget.Label).Text, obj.(*widget.Label).Text)
 }
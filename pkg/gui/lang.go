@@ -0,0 +1,93 @@
+package gui
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"fyne.io/fyne/v2"
+
+	"github.com/fyne-io/defyne/internal/guidefs"
+)
+
+// ExportTranslations writes the source strings of the translated text in a design as JSON keyed by
+// the translation key, in the format that the Fyne `lang` package loads from a "translations" directory.
+// It is an error for a key to be used for different source strings.
+func ExportTranslations(obj fyne.CanvasObject, meta map[fyne.CanvasObject]map[string]string, w io.Writer) error {
+	guidefs.InitOnce()
+
+	texts, err := translationsRequired(obj, meta, make(map[string]string))
+	if err != nil {
+		return err
+	}
+
+	data, err := json.MarshalIndent(texts, "", "  ")
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(append(data, '\n'))
+	return err
+}
+
+// ExportGoPreviewWithTranslations generates a preview like `ExportGoPreview` that loads the translation files
+// before showing the design, so that it can be run in any of their locales.
+func ExportGoPreviewWithTranslations(obj fyne.CanvasObject, meta map[fyne.CanvasObject]map[string]string,
+	translations []fyne.Resource, w io.Writer) error {
+	guidefs.InitOnce()
+
+	return exportGoPreview(obj, meta, translations, w)
+}
+
+// translationsRequired adds the source strings of the translated text in the object tree to texts, keyed by the translation key.
+func translationsRequired(obj fyne.CanvasObject, meta map[fyne.CanvasObject]map[string]string, texts map[string]string) (map[string]string, error) {
+	if obj == nil {
+		return texts, nil
+	}
+
+	class := reflect.TypeOf(obj).String()
+	val := reflect.ValueOf(obj).Elem()
+	for _, field := range guidefs.TranslatableFields[class] {
+		key := guidefs.TranslationKey(meta[obj], field)
+		if key == "" {
+			continue
+		}
+		text := val.FieldByName(field).String()
+		if existing, ok := texts[key]; ok && existing != text {
+			return nil, fmt.Errorf("translation key %q is used for %q and %q", key, existing, text)
+		}
+		texts[key] = text
+	}
+
+	var children []fyne.CanvasObject
+	if c, ok := obj.(*fyne.Container); ok {
+		children = c.Objects
+	} else if info := guidefs.Lookup(class); info != nil && info.IsContainer() {
+		children = info.Children(obj)
+	}
+	for _, child := range children {
+		if _, err := translationsRequired(child, meta, texts); err != nil {
+			return nil, err
+		}
+	}
+	return texts, nil
+}
+
+// translationsGoString returns the Go code that loads the translation files, in name order.
+func translationsGoString(translations []fyne.Resource) string {
+	sorted := append([]fyne.Resource{}, translations...)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].Name() < sorted[j].Name()
+	})
+
+	var code strings.Builder
+	for _, res := range sorted {
+		code.WriteString(fmt.Sprintf("\tif err := lang.AddTranslations(fyne.NewStaticResource(%s, []byte(%s))); err != nil {\n",
+			strconv.Quote(res.Name()), strconv.Quote(string(res.Content()))))
+		code.WriteString("\t\tfyne.LogError(\"Failed to load translations\", err)\n\t}\n")
+	}
+	return code.String()
+}
@@ -0,0 +1,128 @@
+package gui
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/widget"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func translatedDesign() (fyne.CanvasObject, map[fyne.CanvasObject]map[string]string) {
+	l := widget.NewLabel("Welcome")
+	b := widget.NewButton("Save", nil)
+	card := widget.NewCard("Account", "Your details", nil)
+	e := widget.NewEntry()
+	e.SetPlaceHolder("Name")
+	c := container.NewVBox(l, b, card, e)
+
+	return c, map[fyne.CanvasObject]map[string]string{
+		c:    {"layout": "VBox", "dir": "vertical"},
+		l:    {"lang.Text": "Welcome"},
+		b:    {"name": "save", "lang.Text": "button.save"},
+		card: {"lang.Title": "Account", "lang.Subtitle": "account.subtitle"},
+		e:    {"lang.PlaceHolder": "Name"},
+	}
+}
+
+func TestTranslations_EncodeDecode(t *testing.T) {
+	obj, meta := translatedDesign()
+
+	var buf bytes.Buffer
+	require.Nil(t, EncodeObject(obj, meta, &buf))
+	assert.Contains(t, buf.String(), `"Text": "button.save"`)
+
+	obj2, meta2, err := DecodeObject(&buf)
+	require.Nil(t, err)
+	c := obj2.(*fyne.Container)
+	assert.Equal(t, "Welcome", meta2[c.Objects[0]]["lang.Text"])
+	assert.Equal(t, "button.save", meta2[c.Objects[1]]["lang.Text"])
+	assert.Equal(t, "account.subtitle", meta2[c.Objects[2]]["lang.Subtitle"])
+	assert.Equal(t, "Name", meta2[c.Objects[3]]["lang.PlaceHolder"])
+
+	_, _, err = DecodeObject(strings.NewReader(`{"Version": 1, "Object": {"Type": "*widget.Separator", "Translations": {"Text": "x"}, "Struct": {}}}`))
+	require.NotNil(t, err)
+	assert.Equal(t, "Translations.Text: field Text of *widget.Separator cannot be translated", err.Error())
+}
+
+func TestTranslations_ExportGo(t *testing.T) {
+	obj, meta := translatedDesign()
+
+	var buf bytes.Buffer
+	require.Nil(t, ExportGo(obj, meta, "main", &buf))
+	code := buf.String()
+	assert.Contains(t, code, `"fyne.io/fyne/v2/lang"`)
+	assert.Contains(t, code, `widget.NewLabel(lang.L("Welcome"))`)
+	assert.Contains(t, code, `widget.NewButton(lang.X("button.save", "Save"), func() {})`)
+	assert.Contains(t, code, `widget.NewCard(lang.L("Account"), lang.X("account.subtitle", "Your details"), `)
+	assert.Contains(t, code, `PlaceHolder: lang.L("Name")`)
+
+	imported, meta2, err := ImportGo(&buf, "")
+	require.Nil(t, err)
+	c := imported.(*fyne.Container)
+	assert.Equal(t, "Save", c.Objects[1].(*widget.Button).Text)
+	assert.Equal(t, "button.save", meta2[c.Objects[1]]["lang.Text"])
+	assert.Equal(t, "Account", meta2[c.Objects[2]]["lang.Title"])
+	assert.Equal(t, "account.subtitle", meta2[c.Objects[2]]["lang.Subtitle"])
+	assert.Equal(t, "Name", meta2[c.Objects[3]]["lang.PlaceHolder"])
+	_, ok := meta2[c.Objects[0]]["name"]
+	assert.False(t, ok)
+
+	buf.Reset()
+	require.Nil(t, ExportGoPreviewWithTranslations(obj, meta, []fyne.Resource{
+		fyne.NewStaticResource("main.fr.json", []byte(`{"Welcome": "Bienvenue"}`)),
+	}, &buf))
+	assert.Contains(t, buf.String(), `lang.AddTranslations(fyne.NewStaticResource("main.fr.json", []byte("{\"Welcome\": \"Bienvenue\"}")))`)
+}
+
+func TestExportTranslations(t *testing.T) {
+	obj, meta := translatedDesign()
+
+	var buf bytes.Buffer
+	require.Nil(t, ExportTranslations(obj, meta, &buf))
+	assert.Equal(t, `{
+  "Account": "Account",
+  "Name": "Name",
+  "Welcome": "Welcome",
+  "account.subtitle": "Your details",
+  "button.save": "Save"
+}
+`, buf.String())
+
+	c := obj.(*fyne.Container)
+	meta[c.Objects[0]]["lang.Text"] = "button.save"
+	err := ExportTranslations(obj, meta, &buf)
+	require.NotNil(t, err)
+	assert.Equal(t, `translation key "button.save" is used for "Welcome" and "Save"`, err.Error())
+}
+
+func TestTranslations_Editor(t *testing.T) {
+	b := widget.NewButton("Save", nil)
+	props := map[string]string{}
+	items := EditorFor(b, props, nil, nil)
+
+	var translate *widget.FormItem
+	for _, item := range items {
+		if item.Text == "Translate Text" {
+			translate = item
+		}
+	}
+	require.NotNil(t, translate)
+	row := translate.Widget.(*fyne.Container)
+	check := row.Objects[1].(*widget.Check)
+	key := row.Objects[0].(*widget.Entry)
+	assert.True(t, key.Disabled())
+
+	check.SetChecked(true)
+	assert.Equal(t, "Save", props["lang.Text"])
+	key.SetText("button.save")
+	assert.Equal(t, "button.save", props["lang.Text"])
+	check.SetChecked(false)
+	_, ok := props["lang.Text"]
+	assert.False(t, ok)
+}
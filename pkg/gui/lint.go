@@ -0,0 +1,311 @@
+package gui
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/widget"
+
+	"github.com/fyne-io/defyne/internal/guidefs"
+)
+
+// MobilePreviewSize is the size of a phone screen in portrait, designs are laid out at this size to check touch targets.
+var MobilePreviewSize = fyne.NewSize(360, 640)
+
+// touchTargetSize is the smallest width and height of an object that can be tapped reliably on a touch screen.
+// The standard widgets of the default theme are about 36 high, so this reports objects that were sized down.
+const touchTargetSize = 32
+
+// LintRule is a check for a common mistake in a design, such as a button that shows nothing.
+type LintRule struct {
+	Name        string
+	Description string
+
+	check func(l *linter, o *lintObject) string
+}
+
+// LintProblem is an object of a design that breaks a rule.
+type LintProblem struct {
+	Rule    string
+	Object  fyne.CanvasObject
+	Message string
+}
+
+// LintRules lists the rules that `Lint` checks a design with.
+var LintRules = []*LintRule{
+	{Name: "button-content", Description: "Buttons should have text or an icon", check: lintButtonContent},
+	{Name: "entry-label", Description: "Entries should have a placeholder or a label next to them", check: lintEntryLabel},
+	{Name: "touch-target", Description: "Objects that can be tapped should be large enough to touch in the mobile preview",
+		check: lintTouchTarget},
+	{Name: "unused-name", Description: "Named objects should be referenced by an action or the Go code of the package",
+		check: lintUnusedName},
+	{Name: "never-shown", Description: "Hidden objects should be shown by an action or the Go code of the package",
+		check: lintNeverShown},
+}
+
+// Lint checks the objects of a design with each rule in LintRules and returns the problems found, in tree order.
+// The Go code of the package that uses the design can be passed as sources, so that objects used there are not
+// reported as unreferenced. A Fyne app should be running, as the design is laid out to measure its objects.
+func Lint(obj fyne.CanvasObject, meta map[fyne.CanvasObject]map[string]string, sources ...string) []LintProblem {
+	if obj == nil {
+		return nil
+	}
+
+	l := &linter{meta: meta, code: append(designCode(obj, meta), sources...), sizes: previewSizes(obj, meta)}
+	l.walk(&lintObject{obj: obj, shown: obj.Visible()})
+	return l.problems
+}
+
+// LintFile decodes the design at the path and checks it with `Lint`, using the Go files of its directory as sources.
+func LintFile(design string) ([]LintProblem, error) {
+	data, err := os.ReadFile(design)
+	if err != nil {
+		return nil, err
+	}
+	obj, meta, err := DecodeObject(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+
+	sources, err := PackageSources(filepath.Dir(design))
+	if err != nil {
+		return nil, err
+	}
+	return Lint(obj, meta, sources...), nil
+}
+
+// PackageSources returns the Go code of the files in a directory, skipping the code that is generated from designs.
+func PackageSources(dir string) ([]string, error) {
+	files, err := filepath.Glob(filepath.Join(dir, "*.go"))
+	if err != nil {
+		return nil, err
+	}
+
+	var sources []string
+	for _, file := range files {
+		if strings.HasSuffix(file, ".gui.go") || strings.HasSuffix(file, "_gui_test.go") {
+			continue
+		}
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return nil, err
+		}
+		sources = append(sources, string(data))
+	}
+	return sources, nil
+}
+
+// DescribeObject returns a short description of an object for listing problems, such as `Entry "username"`.
+func DescribeObject(obj fyne.CanvasObject, meta map[fyne.CanvasObject]map[string]string) string {
+	if name := meta[obj]["name"]; name != "" {
+		return fmt.Sprintf("%s %q", NameOf(obj), name)
+	}
+
+	switch w := obj.(type) {
+	case *widget.Button:
+		if w.Text != "" {
+			return fmt.Sprintf("Button %q", w.Text)
+		}
+	case *widget.Entry:
+		if w.PlaceHolder != "" {
+			return fmt.Sprintf("%s %q", NameOf(obj), w.PlaceHolder)
+		}
+	}
+	return "unnamed " + NameOf(obj)
+}
+
+// lintObject is an object of the design being checked, with what is known about it from its parents.
+type lintObject struct {
+	obj fyne.CanvasObject
+
+	// label is the text of the label for the object, from a form item or the label before it in a container
+	label string
+	// shown is false if the object or one of its parents is hidden
+	shown bool
+}
+
+type linter struct {
+	meta     map[fyne.CanvasObject]map[string]string
+	code     []string
+	sizes    map[fyne.CanvasObject]fyne.Size
+	problems []LintProblem
+}
+
+func (l *linter) walk(o *lintObject) {
+	for _, rule := range LintRules {
+		if msg := rule.check(l, o); msg != "" {
+			l.problems = append(l.problems, LintProblem{Rule: rule.Name, Object: o.obj,
+				Message: DescribeObject(o.obj, l.meta) + " " + msg})
+		}
+	}
+
+	children, labels := lintChildren(o.obj)
+	for i, child := range children {
+		if child != nil {
+			l.walk(&lintObject{obj: child, label: labels[i], shown: o.shown && child.Visible()})
+		}
+	}
+}
+
+// refers returns true if an action or any of the sources uses a field or method of the gui named ident.
+func (l *linter) refers(ident string) bool {
+	for _, code := range l.code {
+		if refersTo(code, "."+ident) {
+			return true
+		}
+	}
+	return false
+}
+
+// lintChildren returns the children of an object, with the text of the label for each.
+func lintChildren(obj fyne.CanvasObject) ([]fyne.CanvasObject, []string) {
+	if c, ok := obj.(*fyne.Container); ok {
+		labels := make([]string, len(c.Objects))
+		for i := 1; i < len(c.Objects); i++ {
+			if label, ok := c.Objects[i-1].(*widget.Label); ok {
+				labels[i] = label.Text
+			}
+		}
+		return c.Objects, labels
+	}
+
+	info := guidefs.Lookup(reflect.TypeOf(obj).String())
+	if info == nil || !info.IsContainer() {
+		return nil, nil
+	}
+	children := info.Children(obj)
+	labels := make([]string, len(children))
+	if f, ok := obj.(*widget.Form); ok {
+		for i, item := range f.Items {
+			labels[i] = item.Text
+		}
+	}
+	return children, labels
+}
+
+// designCode returns the Go code of the actions in a design, including those of its dialog and main menu.
+func designCode(obj fyne.CanvasObject, meta map[fyne.CanvasObject]map[string]string) []string {
+	var code []string
+	for _, props := range meta {
+		for k, v := range props {
+			if strings.HasPrefix(strings.TrimPrefix(k, dialogPrefix), "On") {
+				code = append(code, v)
+			}
+		}
+	}
+
+	var items func([]*MenuItem)
+	items = func(list []*MenuItem) {
+		for _, item := range list {
+			if item.Action != "" {
+				code = append(code, item.Action)
+			}
+			items(item.Items)
+		}
+	}
+	if win := WindowFor(obj, meta); win != nil {
+		for _, menu := range win.MainMenu {
+			items(menu.Items)
+		}
+	}
+	return code
+}
+
+// previewSizes lays out a copy of the design at MobilePreviewSize and returns the size of each object.
+// Nothing is returned if the design cannot be copied.
+func previewSizes(obj fyne.CanvasObject, meta map[fyne.CanvasObject]map[string]string) map[fyne.CanvasObject]fyne.Size {
+	var buf bytes.Buffer
+	if err := EncodeObject(obj, meta, &buf); err != nil {
+		return nil
+	}
+	clone, _, err := DecodeObject(&buf)
+	if err != nil {
+		return nil
+	}
+	clone.Resize(MobilePreviewSize)
+
+	sizes := make(map[fyne.CanvasObject]fyne.Size)
+	var walk func(o, c fyne.CanvasObject)
+	walk = func(o, c fyne.CanvasObject) {
+		if o == nil || c == nil || reflect.TypeOf(o) != reflect.TypeOf(c) {
+			return
+		}
+		sizes[o] = c.Size()
+
+		children, _ := lintChildren(o)
+		copies, _ := lintChildren(c)
+		if len(children) != len(copies) {
+			return
+		}
+		for i := range children {
+			walk(children[i], copies[i])
+		}
+	}
+	walk(obj, clone)
+	return sizes
+}
+
+func lintButtonContent(_ *linter, o *lintObject) string {
+	if b, ok := o.obj.(*widget.Button); ok && b.Text == "" && b.Icon == nil {
+		return "has neither text nor an icon"
+	}
+	return ""
+}
+
+func lintEntryLabel(_ *linter, o *lintObject) string {
+	if e, ok := o.obj.(*widget.Entry); ok && e.PlaceHolder == "" && o.label == "" {
+		return "has no placeholder or label"
+	}
+	return ""
+}
+
+func lintTouchTarget(l *linter, o *lintObject) string {
+	if _, ok := o.obj.(fyne.Tappable); !ok || !o.shown {
+		return ""
+	}
+	size, ok := l.sizes[o.obj]
+	if !ok || size.IsZero() { // not laid out, such as the content of another tab
+		return ""
+	}
+
+	if size.Width < touchTargetSize || size.Height < touchTargetSize {
+		return fmt.Sprintf("is %gx%g in the mobile preview, smaller than the %dx%d touch target",
+			size.Width, size.Height, touchTargetSize, touchTargetSize)
+	}
+	return ""
+}
+
+func lintUnusedName(l *linter, o *lintObject) string {
+	name := l.meta[o.obj]["name"]
+	if name == "" || l.refers(name) {
+		return ""
+	}
+	return "is named but never referenced"
+}
+
+// showCall matches the code that shows a hidden object, the name of the object is the first group.
+var showCall = regexp.MustCompile(`\.([a-zA-Z_][a-zA-Z0-9_]*)\.Show\(`)
+
+func lintNeverShown(l *linter, o *lintObject) string {
+	if o.obj.Visible() {
+		return ""
+	}
+
+	name := l.meta[o.obj]["name"]
+	if name != "" {
+		for _, code := range l.code {
+			for _, match := range showCall.FindAllStringSubmatch(code, -1) {
+				if match[1] == name {
+					return ""
+				}
+			}
+		}
+	}
+	return "is hidden and never shown"
+}
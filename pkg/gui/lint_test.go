@@ -0,0 +1,115 @@
+package gui
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/layout"
+	"fyne.io/fyne/v2/test"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// lintRules returns the rule of each problem, in order.
+func lintRules(problems []LintProblem) []string {
+	rules := make([]string, len(problems))
+	for i, p := range problems {
+		rules[i] = p.Rule
+	}
+	return rules
+}
+
+func TestLint(t *testing.T) {
+	test.NewTempApp(t)
+
+	empty := widget.NewButton("", nil)
+	icon := widget.NewButtonWithIcon("", theme.HomeIcon(), nil)
+	unlabelled := widget.NewEntry()
+	labelled := widget.NewEntry()
+	placeholder := widget.NewEntry()
+	placeholder.SetPlaceHolder("Search")
+	form := widget.NewForm(widget.NewFormItem("Email", widget.NewEntry()))
+	box := container.NewVBox(empty, icon, unlabelled, widget.NewLabel("Name"), labelled, placeholder, form)
+	meta := map[fyne.CanvasObject]map[string]string{box: {"layout": "VBox", "dir": "vertical"}}
+
+	problems := Lint(box, meta)
+	assert.Equal(t, []string{"button-content", "entry-label"}, lintRules(problems))
+	assert.Equal(t, empty, problems[0].Object)
+	assert.Equal(t, "unnamed Button has neither text nor an icon", problems[0].Message)
+	assert.Equal(t, unlabelled, problems[1].Object)
+}
+
+func TestLint_TouchTarget(t *testing.T) {
+	test.NewTempApp(t)
+
+	small := widget.NewButton("Go", nil)
+	big := widget.NewButton("Go", nil)
+	grid := container.NewGridWrap(fyne.NewSize(20, 20), small)
+	box := container.NewVBox(grid, big)
+	meta := map[fyne.CanvasObject]map[string]string{
+		box:   {"layout": "VBox", "dir": "vertical"},
+		grid:  {"layout": "GridWrap", "width": "20", "height": "20"},
+		small: {"name": "small", "OnTapped": "g.small.Disable()"},
+	}
+
+	problems := Lint(box, meta)
+	require.Equal(t, []string{"touch-target"}, lintRules(problems))
+	assert.Equal(t, small, problems[0].Object)
+	assert.Equal(t, `Button "small" is 20x20 in the mobile preview, smaller than the 32x32 touch target`,
+		problems[0].Message)
+}
+
+func TestLint_Names(t *testing.T) {
+	test.NewTempApp(t)
+
+	unused := widget.NewLabel("Unused")
+	status := widget.NewLabel("Status")
+	hidden := widget.NewLabel("Hidden")
+	hidden.Hide()
+	shown := widget.NewLabel("Shown")
+	shown.Hide()
+	save := widget.NewButton("Save", nil)
+	box := container.New(layout.NewVBoxLayout(), unused, status, hidden, shown, save)
+	meta := map[fyne.CanvasObject]map[string]string{
+		box:    {"layout": "VBox", "dir": "vertical"},
+		unused: {"name": "unused"},
+		status: {"name": "status"},
+		hidden: {"name": "hidden"},
+		shown:  {"name": "shown"},
+		save:   {"OnTapped": "g.status.SetText(\"Saved\"); g.hidden.Refresh()"},
+	}
+
+	problems := Lint(box, meta, "func (g *gui) onLoad() {\n\tg.shown.Show()\n}\n")
+	assert.Equal(t, []string{"unused-name", "never-shown"}, lintRules(problems))
+	assert.Equal(t, unused, problems[0].Object)
+	assert.Equal(t, hidden, problems[1].Object)
+	assert.Equal(t, `Label "hidden" is hidden and never shown`, problems[1].Message)
+}
+
+func TestLintFile(t *testing.T) {
+	test.NewTempApp(t)
+
+	dir := t.TempDir()
+	design := filepath.Join(dir, "main.gui.json")
+	require.Nil(t, os.WriteFile(design, []byte(fmt.Sprintf(labelJSON, "\n  \"Name\": \"title\",")), 0644))
+	require.Nil(t, os.WriteFile(filepath.Join(dir, "main.gui.go"), []byte("package main\n\nvar _ = g.title\n"), 0644))
+
+	problems, err := LintFile(design)
+	require.Nil(t, err)
+	assert.Equal(t, []string{"unused-name"}, lintRules(problems))
+
+	require.Nil(t, os.WriteFile(filepath.Join(dir, "main.go"), []byte("package main\n\nvar _ = g.title\n"), 0644))
+	problems, err = LintFile(design)
+	require.Nil(t, err)
+	assert.Empty(t, problems)
+
+	_, err = LintFile(filepath.Join(dir, "missing.gui.json"))
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,112 @@
+package gui
+
+import (
+	"fmt"
+	"io"
+	"reflect"
+	"sort"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/storage"
+)
+
+// Design is a user interface that was loaded from a .gui.json file at runtime, instead of generating Go code.
+type Design struct {
+	content fyne.CanvasObject
+	meta    map[fyne.CanvasObject]map[string]string
+	names   map[string]fyne.CanvasObject
+}
+
+// Load reads a design from the JSON `Reader` and connects its actions to the functions in `handlers`.
+// An action, such as `OnTapped`, is looked up first by the object name and action, like "save.OnTapped",
+// then by the code stored for the action with any "g." prefix removed, so that "g.onSave" uses the "onSave" handler.
+// Each handler must be a function of the same type as the field, for example `func()` for `OnTapped`.
+// Actions that have no handler are left unconnected.
+func Load(r io.Reader, handlers map[string]interface{}) (*Design, error) {
+	obj, meta, err := DecodeObject(r)
+	if err != nil {
+		return nil, err
+	}
+
+	d := &Design{content: obj, meta: meta, names: make(map[string]fyne.CanvasObject)}
+	var problems []string
+	for o, props := range meta {
+		if name := props["name"]; name != "" {
+			d.names[name] = o
+		}
+
+		for k := range props {
+			if len(k) > 2 && k[0:2] == "On" {
+				if err := connectAction(o, k, props, handlers); err != nil {
+					problems = append(problems, err.Error())
+				}
+			}
+		}
+	}
+
+	if len(problems) > 0 {
+		sort.Strings(problems)
+		return nil, fmt.Errorf("failed to connect actions: %s", strings.Join(problems, ", "))
+	}
+	return d, nil
+}
+
+// LoadURI reads a design from the file at the given URI, like `Load`.
+func LoadURI(u fyne.URI, handlers map[string]interface{}) (*Design, error) {
+	r, err := storage.Reader(u)
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+
+	return Load(r, handlers)
+}
+
+// Content returns the root object of the design.
+func (d *Design) Content() fyne.CanvasObject {
+	return d.content
+}
+
+// Object returns the object in the design with the given name, or nil if there is no such object.
+func (d *Design) Object(name string) fyne.CanvasObject {
+	return d.names[name]
+}
+
+// Names returns the names of all of the named objects in the design, in alphabetical order.
+func (d *Design) Names() []string {
+	names := make([]string, 0, len(d.names))
+	for name := range d.names {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func connectAction(obj fyne.CanvasObject, action string, props map[string]string, handlers map[string]interface{}) error {
+	var handler interface{}
+	ok := false
+	label := action
+	if name := props["name"]; name != "" {
+		label = name + "." + action
+		handler, ok = handlers[label]
+	}
+	if !ok {
+		handler, ok = handlers[strings.TrimPrefix(props[action], "g.")]
+	}
+	if !ok {
+		return nil
+	}
+
+	f := reflect.ValueOf(obj).Elem().FieldByName(action)
+	if !f.IsValid() || f.Kind() != reflect.Func {
+		return fmt.Errorf("%s is not an action of %T", label, obj)
+	}
+
+	fn := reflect.ValueOf(handler)
+	if !fn.IsValid() || !fn.Type().AssignableTo(f.Type()) {
+		return fmt.Errorf("handler for %s is %T, expected %s", label, handler, f.Type())
+	}
+	f.Set(fn)
+	return nil
+}
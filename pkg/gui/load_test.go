@@ -0,0 +1,64 @@
+package gui
+
+import (
+	"bytes"
+	"testing"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/test"
+	"fyne.io/fyne/v2/widget"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func actionDesign(t *testing.T) []byte {
+	save := widget.NewButton("Save", nil)
+	quit := widget.NewButton("Quit", nil)
+	agree := widget.NewCheck("Agree", nil)
+	c := container.NewVBox(save, quit, agree)
+
+	meta := map[fyne.CanvasObject]map[string]string{
+		c:     {"layout": "VBox", "dir": "vertical"},
+		save:  {"name": "save", "OnTapped": "g.onSave"},
+		quit:  {"OnTapped": "func() {}"},
+		agree: {"name": "agree", "OnChanged": "func(bool) {}"},
+	}
+
+	var buf bytes.Buffer
+	require.Nil(t, EncodeObject(c, meta, &buf))
+	return buf.Bytes()
+}
+
+func TestLoad(t *testing.T) {
+	saved, agreed := false, false
+	d, err := Load(bytes.NewReader(actionDesign(t)), map[string]interface{}{
+		"onSave":          func() { saved = true },
+		"agree.OnChanged": func(on bool) { agreed = on },
+	})
+	require.Nil(t, err)
+
+	assert.IsType(t, &fyne.Container{}, d.Content())
+	assert.Equal(t, []string{"agree", "save"}, d.Names())
+	assert.Nil(t, d.Object("missing"))
+
+	save := d.Object("save").(*widget.Button)
+	assert.Equal(t, "Save", save.Text)
+	test.Tap(save)
+	assert.True(t, saved)
+
+	test.Tap(d.Object("agree").(*widget.Check))
+	assert.True(t, agreed)
+}
+
+func TestLoad_Errors(t *testing.T) {
+	_, err := Load(bytes.NewReader(actionDesign(t)), map[string]interface{}{
+		"onSave": func(string) {},
+	})
+	require.NotNil(t, err)
+	assert.Equal(t, "failed to connect actions: handler for save.OnTapped is func(string), expected func()", err.Error())
+
+	_, err = Load(bytes.NewReader([]byte(`{"Version": 1, "Object": {"Type": "*widget.Nope"}}`)), nil)
+	assert.NotNil(t, err)
+}
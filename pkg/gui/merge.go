@@ -0,0 +1,482 @@
+package gui
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// mergeKeys are the fields that identify an element of an array, so that objects can be matched
+// when they are moved, added or removed. Elements without one of them are matched by comparing the arrays.
+var mergeKeys = []string{"Name", "ID", "Src"}
+
+// MergeDocuments merges the changes made to a design in two branches, ours and theirs, since the common base.
+// Changes to different objects and fields are combined, the design tree is merged node by node.
+// Where both branches changed the same value differently the result contains git style conflict markers
+// around both versions and the number of conflicts is returned.
+func MergeDocuments(base, ours, theirs []byte) ([]byte, int, error) {
+	var docs [3]*jsonObject
+	for i, data := range [][]byte{base, ours, theirs} {
+		doc, err := parseOrdered(canonicalDocument(data))
+		if err != nil {
+			return nil, 0, err
+		}
+		docs[i] = doc
+	}
+
+	m := &merger{}
+	merged := m.mergeValue(docs[0], docs[1], docs[2])
+	var buf bytes.Buffer
+	writeMerged(&buf, merged, "")
+	buf.WriteByte('\n')
+	return buf.Bytes(), m.conflicts, nil
+}
+
+// canonicalDocument re-encodes a design in the current format, so that only real changes are merged.
+// Documents that cannot be decoded are merged as they are.
+func canonicalDocument(data []byte) []byte {
+	obj, meta, err := DecodeObject(bytes.NewReader(data))
+	if err != nil {
+		return data
+	}
+
+	var buf bytes.Buffer
+	if EncodeObject(obj, meta, &buf) != nil {
+		return data
+	}
+	return buf.Bytes()
+}
+
+// jsonObject is a JSON object that remembers the order of its keys, so a merge keeps the layout of the files.
+type jsonObject struct {
+	keys   []string
+	values map[string]interface{}
+}
+
+// mergeConflict is a value that was changed differently by both branches, a missing side was removed.
+type mergeConflict struct {
+	ours, theirs       interface{}
+	hasOurs, hasTheirs bool
+}
+
+type merger struct {
+	conflicts int
+}
+
+func (m *merger) conflict(ours, theirs interface{}, hasOurs, hasTheirs bool) *mergeConflict {
+	m.conflicts++
+	return &mergeConflict{ours: ours, theirs: theirs, hasOurs: hasOurs, hasTheirs: hasTheirs}
+}
+
+func (m *merger) mergeValue(base, ours, theirs interface{}) interface{} {
+	switch {
+	case jsonEqual(ours, theirs), jsonEqual(base, theirs):
+		return ours
+	case jsonEqual(base, ours):
+		return theirs
+	}
+
+	b, bok := base.(*jsonObject)
+	o, ook := ours.(*jsonObject)
+	t, tok := theirs.(*jsonObject)
+	if ook && tok {
+		if !bok {
+			b = &jsonObject{values: map[string]interface{}{}}
+		}
+		return m.mergeObject(b, o, t)
+	}
+
+	bList, _ := base.([]interface{})
+	oList, ook := ours.([]interface{})
+	tList, tok := theirs.([]interface{})
+	if ook && tok {
+		return m.mergeList(bList, oList, tList)
+	}
+	return m.conflict(ours, theirs, true, true)
+}
+
+// mergeObject merges each field, keeping the order of ours with fields that only theirs has added at the end.
+func (m *merger) mergeObject(base, ours, theirs *jsonObject) *jsonObject {
+	merged := &jsonObject{values: map[string]interface{}{}}
+	keys := append([]string{}, ours.keys...)
+	for _, k := range theirs.keys {
+		if _, ok := ours.values[k]; !ok {
+			keys = append(keys, k)
+		}
+	}
+
+	for _, k := range keys {
+		b, inBase := base.values[k]
+		o, inOurs := ours.values[k]
+		t, inTheirs := theirs.values[k]
+		var v interface{}
+		switch {
+		case inOurs && inTheirs:
+			v = m.mergeValue(b, o, t)
+		case inBase && jsonEqual(b, o), inBase && jsonEqual(b, t):
+			continue // removed by one branch and not changed by the other
+		case inBase:
+			v = m.conflict(o, t, inOurs, inTheirs)
+		case inOurs:
+			v = o
+		default:
+			v = t
+		}
+
+		merged.keys = append(merged.keys, k)
+		merged.values[k] = v
+	}
+	return merged
+}
+
+// mergeList merges arrays element by element, after matching the elements of each version with `elementIDs`.
+func (m *merger) mergeList(base, ours, theirs []interface{}) []interface{} {
+	bKeys, oKeys, tKeys := elementIDs(base, ours, theirs)
+
+	var keys []string
+	var values []interface{}
+	for i, k := range oKeys {
+		bi, inBase := indexOf(bKeys, k)
+		ti, inTheirs := indexOf(tKeys, k)
+		switch {
+		case inTheirs && inBase:
+			values = append(values, m.mergeValue(base[bi], ours[i], theirs[ti]))
+		case inTheirs:
+			values = append(values, m.mergeValue(nil, ours[i], theirs[ti]))
+		case inBase && jsonEqual(base[bi], ours[i]):
+			continue // removed by theirs
+		case inBase:
+			values = append(values, m.conflict(ours[i], nil, true, false))
+		default:
+			values = append(values, ours[i])
+		}
+		keys = append(keys, k)
+	}
+
+	for i, k := range tKeys {
+		if _, inOurs := indexOf(oKeys, k); inOurs {
+			continue
+		}
+		bi, inBase := indexOf(bKeys, k)
+		var v interface{}
+		switch {
+		case inBase && jsonEqual(base[bi], theirs[i]):
+			continue // removed by ours
+		case inBase:
+			v = m.conflict(nil, theirs[i], false, true)
+		default:
+			v = theirs[i]
+		}
+
+		// insert after the element that comes before it in theirs
+		at := 0
+		for j := i - 1; j >= 0; j-- {
+			if prev, ok := indexOf(keys, tKeys[j]); ok {
+				at = prev + 1
+				break
+			}
+		}
+		keys = append(keys[:at], append([]string{k}, keys[at:]...)...)
+		values = append(values[:at], append([]interface{}{v}, values[at:]...)...)
+	}
+	return values
+}
+
+// elementIDs returns an identity for each element of the three versions of an array, matching elements share one.
+// Base elements are matched in each branch by `matchElements`, elements that both branches added are matched
+// if they have the same key or are equal.
+func elementIDs(base, ours, theirs []interface{}) ([]string, []string, []string) {
+	bKeys, oKeys, tKeys := listKeys(base), listKeys(ours), listKeys(theirs)
+	bIDs := make([]string, len(base))
+	for i := range base {
+		bIDs[i] = "base" + strconv.Itoa(i)
+	}
+
+	ids := func(list []interface{}, keys []string, prefix string) []string {
+		ret := make([]string, len(list))
+		for i, b := range matchElements(base, list, bKeys, keys) {
+			if b >= 0 {
+				ret[i] = bIDs[b]
+			} else {
+				ret[i] = prefix + strconv.Itoa(i)
+			}
+		}
+		return ret
+	}
+	oIDs := ids(ours, oKeys, "ours")
+	tIDs := ids(theirs, tKeys, "theirs")
+
+	for i, id := range oIDs {
+		if !strings.HasPrefix(id, "ours") {
+			continue
+		}
+		for j, other := range tIDs {
+			if strings.HasPrefix(other, "theirs") && sameElement(ours[i], theirs[j], oKeys[i], tKeys[j]) {
+				tIDs[j] = id
+				break
+			}
+		}
+	}
+	return bIDs, oIDs, tIDs
+}
+
+// matchElements returns the index of the element in `from` that each element of `to` matches, or -1 if it was added.
+// Elements with a key are matched by the key wherever they are. Elements without one are matched by the longest
+// common subsequence of equal elements, and those left between two matches are paired in order as changed elements.
+func matchElements(from, to []interface{}, fromKeys, toKeys []string) []int {
+	match := make([]int, len(to))
+	var fromFree, toFree []int
+	for i, k := range toKeys {
+		match[i] = -1
+		if k == "" {
+			toFree = append(toFree, i)
+		} else if j, ok := indexOf(fromKeys, k); ok {
+			match[i] = j
+		}
+	}
+	for j, k := range fromKeys {
+		if k == "" {
+			fromFree = append(fromFree, j)
+		}
+	}
+
+	equal := func(f, t int) bool {
+		return jsonEqual(from[fromFree[f]], to[toFree[t]])
+	}
+	common := make([][]int, len(fromFree)+1)
+	for f := range common {
+		common[f] = make([]int, len(toFree)+1)
+	}
+	for f := len(fromFree) - 1; f >= 0; f-- {
+		for t := len(toFree) - 1; t >= 0; t-- {
+			switch {
+			case equal(f, t):
+				common[f][t] = common[f+1][t+1] + 1
+			case common[f+1][t] >= common[f][t+1]:
+				common[f][t] = common[f+1][t]
+			default:
+				common[f][t] = common[f][t+1]
+			}
+		}
+	}
+
+	gapF, gapT := 0, 0
+	pairGap := func(endF, endT int) {
+		for ; gapF < endF && gapT < endT; gapF, gapT = gapF+1, gapT+1 {
+			match[toFree[gapT]] = fromFree[gapF]
+		}
+	}
+	f, t := 0, 0
+	for f < len(fromFree) && t < len(toFree) {
+		switch {
+		case equal(f, t):
+			pairGap(f, t)
+			match[toFree[t]] = fromFree[f]
+			f, t = f+1, t+1
+			gapF, gapT = f, t
+		case common[f+1][t] >= common[f][t+1]:
+			f++
+		default:
+			t++
+		}
+	}
+	pairGap(len(fromFree), len(toFree))
+	return match
+}
+
+// sameElement returns true if two array elements are the same object, by their keys if either has one.
+func sameElement(a, b interface{}, aKey, bKey string) bool {
+	if aKey != "" || bKey != "" {
+		return aKey == bKey
+	}
+	return jsonEqual(a, b)
+}
+
+// listKeys returns the identifying key of each element, which is blank if it has none or the key is not unique.
+func listKeys(list []interface{}) []string {
+	keys := make([]string, len(list))
+	count := make(map[string]int)
+	for i, v := range list {
+		obj, ok := v.(*jsonObject)
+		if !ok {
+			continue
+		}
+		for _, field := range mergeKeys {
+			if s, ok := obj.values[field].(string); ok && s != "" {
+				keys[i] = field + "=" + s
+				count[keys[i]]++
+				break
+			}
+		}
+	}
+
+	for i, k := range keys {
+		if count[k] > 1 {
+			keys[i] = ""
+		}
+	}
+	return keys
+}
+
+func indexOf(list []string, s string) (int, bool) {
+	for i, item := range list {
+		if item == s {
+			return i, true
+		}
+	}
+	return -1, false
+}
+
+// jsonEqual compares two parsed JSON values, the order of object keys is ignored.
+func jsonEqual(a, b interface{}) bool {
+	switch av := a.(type) {
+	case *jsonObject:
+		bv, ok := b.(*jsonObject)
+		if !ok || len(av.values) != len(bv.values) {
+			return false
+		}
+		for k, v := range av.values {
+			other, ok := bv.values[k]
+			if !ok || !jsonEqual(v, other) {
+				return false
+			}
+		}
+		return true
+	case []interface{}:
+		bv, ok := b.([]interface{})
+		if !ok || len(av) != len(bv) {
+			return false
+		}
+		for i := range av {
+			if !jsonEqual(av[i], bv[i]) {
+				return false
+			}
+		}
+		return true
+	}
+	return a == b
+}
+
+// parseOrdered parses a JSON document whose root is an object, keeping the order of keys.
+func parseOrdered(data []byte) (*jsonObject, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	v, err := parseValue(dec)
+	if err != nil {
+		return nil, err
+	}
+	if _, err = dec.Token(); err != io.EOF {
+		return nil, errors.New("unexpected data after the document")
+	}
+
+	obj, ok := v.(*jsonObject)
+	if !ok {
+		return nil, errors.New("document must be a JSON object")
+	}
+	return obj, nil
+}
+
+func parseValue(dec *json.Decoder) (interface{}, error) {
+	tok, err := dec.Token()
+	if err != nil {
+		return nil, err
+	}
+
+	switch tok {
+	case json.Delim('{'):
+		obj := &jsonObject{values: map[string]interface{}{}}
+		for dec.More() {
+			key, err := dec.Token()
+			if err != nil {
+				return nil, err
+			}
+			v, err := parseValue(dec)
+			if err != nil {
+				return nil, err
+			}
+
+			k := key.(string)
+			if _, dup := obj.values[k]; !dup {
+				obj.keys = append(obj.keys, k)
+			}
+			obj.values[k] = v
+		}
+		_, err = dec.Token()
+		return obj, err
+	case json.Delim('['):
+		list := []interface{}{}
+		for dec.More() {
+			v, err := parseValue(dec)
+			if err != nil {
+				return nil, err
+			}
+			list = append(list, v)
+		}
+		_, err = dec.Token()
+		return list, err
+	}
+	return tok, nil
+}
+
+// writeMerged writes a merged value in the indented layout of `EncodeObject`.
+// Conflicts are written as the lines of both versions between git style markers.
+func writeMerged(w *bytes.Buffer, v interface{}, indent string) {
+	switch val := v.(type) {
+	case *jsonObject:
+		if len(val.keys) == 0 {
+			w.WriteString("{}")
+			return
+		}
+
+		w.WriteString("{\n")
+		for i, k := range val.keys {
+			name, _ := json.Marshal(k)
+			writeMember(w, string(name)+": ", val.values[k], indent+"  ", i < len(val.keys)-1)
+		}
+		w.WriteString(indent + "}")
+	case []interface{}:
+		if len(val) == 0 {
+			w.WriteString("[]")
+			return
+		}
+
+		w.WriteString("[\n")
+		for i, item := range val {
+			writeMember(w, "", item, indent+"  ", i < len(val)-1)
+		}
+		w.WriteString(indent + "]")
+	default:
+		data, _ := json.Marshal(val)
+		w.Write(data)
+	}
+}
+
+// writeMember writes one line of an object or array, with the prefix of the key if it is in an object.
+func writeMember(w *bytes.Buffer, prefix string, v interface{}, indent string, more bool) {
+	side := func(v interface{}, ok bool) {
+		if !ok {
+			return
+		}
+		w.WriteString(indent + prefix)
+		writeMerged(w, v, indent)
+		if more {
+			w.WriteByte(',')
+		}
+		w.WriteByte('\n')
+	}
+
+	c, ok := v.(*mergeConflict)
+	if !ok {
+		side(v, true)
+		return
+	}
+
+	w.WriteString("<<<<<<< ours\n")
+	side(c.ours, c.hasOurs)
+	w.WriteString("=======\n")
+	side(c.theirs, c.hasTheirs)
+	w.WriteString(">>>>>>> theirs\n")
+}
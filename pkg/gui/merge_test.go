@@ -0,0 +1,124 @@
+package gui
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/widget"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// mergeDesign encodes a box of named labels, the text of each label is given by name.
+func mergeDesign(t *testing.T, names []string, texts map[string]string) []byte {
+	box := container.NewVBox()
+	meta := map[fyne.CanvasObject]map[string]string{box: {"layout": "VBox", "dir": "vertical"}}
+	for _, name := range names {
+		l := widget.NewLabel(texts[name])
+		box.Add(l)
+		meta[l] = map[string]string{"name": name}
+	}
+
+	var buf bytes.Buffer
+	require.Nil(t, EncodeObject(box, meta, &buf))
+	return buf.Bytes()
+}
+
+func TestMergeDocuments(t *testing.T) {
+	texts := map[string]string{"title": "Hello", "body": "Text", "footer": "Bye"}
+	base := mergeDesign(t, []string{"title", "body", "footer"}, texts)
+	ours := mergeDesign(t, []string{"title", "body", "footer"},
+		map[string]string{"title": "Welcome", "body": "Text", "footer": "Bye"})
+	theirs := mergeDesign(t, []string{"title", "subtitle", "body"},
+		map[string]string{"title": "Hello", "subtitle": "New", "body": "Text"})
+
+	merged, conflicts, err := MergeDocuments(base, ours, theirs)
+	require.Nil(t, err)
+	assert.Equal(t, 0, conflicts)
+
+	expected := mergeDesign(t, []string{"title", "subtitle", "body"},
+		map[string]string{"title": "Welcome", "subtitle": "New", "body": "Text"})
+	assert.Equal(t, string(expected), string(merged))
+}
+
+// mergeUnnamedDesign encodes a box of labels without names, with the texts given.
+func mergeUnnamedDesign(t *testing.T, texts ...string) []byte {
+	box := container.NewVBox()
+	for _, text := range texts {
+		box.Add(widget.NewLabel(text))
+	}
+	meta := map[fyne.CanvasObject]map[string]string{box: {"layout": "VBox", "dir": "vertical"}}
+
+	var buf bytes.Buffer
+	require.Nil(t, EncodeObject(box, meta, &buf))
+	return buf.Bytes()
+}
+
+func TestMergeDocuments_Unnamed(t *testing.T) {
+	base := mergeUnnamedDesign(t, "A", "B")
+	merged, conflicts, err := MergeDocuments(base, mergeUnnamedDesign(t, "A2", "B"), mergeUnnamedDesign(t, "A", "B", "C"))
+	require.Nil(t, err)
+	assert.Equal(t, 0, conflicts)
+	assert.Equal(t, string(mergeUnnamedDesign(t, "A2", "B", "C")), string(merged))
+
+	merged, conflicts, err = MergeDocuments(base, mergeUnnamedDesign(t, "B"), mergeUnnamedDesign(t, "C", "A", "B2"))
+	require.Nil(t, err)
+	assert.Equal(t, 0, conflicts)
+	assert.Equal(t, string(mergeUnnamedDesign(t, "C", "B2")), string(merged))
+
+	merged, conflicts, err = MergeDocuments(base, mergeUnnamedDesign(t, "A", "B", "C"), mergeUnnamedDesign(t, "A", "B", "C"))
+	require.Nil(t, err)
+	assert.Equal(t, 0, conflicts)
+	assert.Equal(t, string(mergeUnnamedDesign(t, "A", "B", "C")), string(merged))
+
+	merged, conflicts, err = MergeDocuments(base, mergeUnnamedDesign(t, "A1", "B"), mergeUnnamedDesign(t, "A2", "B", "C"))
+	require.Nil(t, err)
+	assert.Equal(t, 1, conflicts)
+	out := string(merged)
+	assert.Contains(t, out, `<<<<<<< ours
+          "Text": "A1"
+=======
+          "Text": "A2"
+>>>>>>> theirs
+`)
+	assert.Equal(t, 1, strings.Count(out, `"Text": "B"`))
+	assert.Contains(t, out, `"Text": "C"`)
+}
+
+func TestMergeDocuments_Conflicts(t *testing.T) {
+	names := []string{"title", "footer"}
+	base := mergeDesign(t, names, map[string]string{"title": "Hello", "footer": "Bye"})
+	ours := mergeDesign(t, []string{"title"}, map[string]string{"title": "Welcome"})
+	theirs := mergeDesign(t, names, map[string]string{"title": "Hi", "footer": "See you"})
+
+	merged, conflicts, err := MergeDocuments(base, ours, theirs)
+	require.Nil(t, err)
+	assert.Equal(t, 2, conflicts)
+	out := string(merged)
+	assert.Contains(t, out, `<<<<<<< ours
+          "Text": "Welcome"
+=======
+          "Text": "Hi"
+>>>>>>> theirs
+`)
+	assert.Contains(t, out, "<<<<<<< ours\n=======\n")
+	assert.Contains(t, out, `"Text": "See you"`)
+
+	_, _, err = DecodeObject(strings.NewReader(out))
+	assert.NotNil(t, err)
+}
+
+func TestMergeDocuments_Unchanged(t *testing.T) {
+	base := mergeDesign(t, []string{"title"}, map[string]string{"title": "Hello"})
+	merged, conflicts, err := MergeDocuments(base, base, base)
+	require.Nil(t, err)
+	assert.Equal(t, 0, conflicts)
+	assert.Equal(t, string(base), string(merged))
+
+	_, _, err = MergeDocuments(base, []byte("[1]"), base)
+	assert.NotNil(t, err)
+}
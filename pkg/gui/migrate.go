@@ -0,0 +1,143 @@
+package gui
+
+import (
+	"fmt"
+	"strconv"
+
+	"fyne.io/fyne/v2"
+
+	"github.com/fyne-io/defyne/internal/guidefs"
+)
+
+// FormatVersion is the version of the .gui.json format written by `EncodeObject`.
+// Older documents are migrated to this version when they are decoded.
+const FormatVersion = 3
+
+// migrations upgrade a document by one version each, the entry at index i moves a document from version i to i+1.
+var migrations = []func(doc map[string]interface{}) map[string]interface{}{
+	migrateV0,
+	migrateV1,
+	migrateV2,
+}
+
+type document struct {
+	Version  int
+	TestSize *fyne.Size `json:",omitempty"`
+	Dialog   *Dialog    `json:",omitempty"`
+	Window   *Window    `json:",omitempty"`
+	Handlers bool       `json:",omitempty"`
+	Object   interface{}
+}
+
+// MigrateDocument upgrades the decoded JSON of a .gui.json file to the current `FormatVersion`.
+// Documents written before versioning was added are detected and wrapped in the current document layout.
+func MigrateDocument(doc map[string]interface{}) (map[string]interface{}, error) {
+	version := documentVersion(doc)
+	if version < 0 {
+		return nil, fmt.Errorf("invalid file format version %d", version)
+	} else if version > FormatVersion {
+		return nil, fmt.Errorf("file format version %d is newer than the supported version %d", version, FormatVersion)
+	}
+
+	for ; version < FormatVersion; version++ {
+		doc = migrations[version](doc)
+		doc["Version"] = float64(version + 1)
+	}
+	return doc, nil
+}
+
+func documentVersion(doc map[string]interface{}) int {
+	v, ok := doc["Version"].(float64)
+	if !ok {
+		return 0 // unversioned files have the object at the root
+	}
+
+	return int(v)
+}
+
+// migrateV0 wraps the root object in a versioned document and renames types and layouts that were replaced in Fyne.
+func migrateV0(root map[string]interface{}) map[string]interface{} {
+	walkNodes(root, func(node map[string]interface{}) {
+		switch node["Type"] {
+		case "*widget.Scroll":
+			node["Type"] = "*container.Scroll"
+		case "*fyne.Container":
+			if node["Layout"] == "Max" {
+				node["Layout"] = "Stack"
+			}
+		}
+	})
+
+	return map[string]interface{}{"Object": root}
+}
+
+// migrateV1 replaces the child indexes that border containers stored for their edges with the node IDs of the children,
+// giving an ID to each child that is referenced. Edges that referred to a missing child are cleared.
+func migrateV1(doc map[string]interface{}) map[string]interface{} {
+	used := make(map[string]bool)
+	walkNodes(doc, func(node map[string]interface{}) {
+		if id, ok := node["ID"].(string); ok {
+			used[id] = true
+		}
+	})
+
+	walkNodes(doc, func(node map[string]interface{}) {
+		props, ok := node["Properties"].(map[string]interface{})
+		if node["Type"] != "*fyne.Container" || node["Layout"] != "Border" || !ok {
+			return
+		}
+
+		// indexes counted the children that were decoded, null objects were skipped
+		var children []map[string]interface{}
+		objs, _ := node["Objects"].([]interface{})
+		for _, o := range objs {
+			if child, ok := o.(map[string]interface{}); ok {
+				children = append(children, child)
+			}
+		}
+
+		for _, edge := range []string{"top", "bottom", "left", "right"} {
+			index, ok := props[edge].(string)
+			if !ok || index == "" {
+				continue
+			}
+			i, err := strconv.Atoi(index)
+			if err != nil || i < 0 || i >= len(children) {
+				props[edge] = ""
+				continue
+			}
+
+			id, _ := children[i]["ID"].(string)
+			if id == "" {
+				id = guidefs.UnusedNodeID(used)
+				used[id] = true
+				children[i]["ID"] = id
+			}
+			props[edge] = id
+		}
+	})
+	return doc
+}
+
+// migrateV2 does not change the JSON. Version 2 documents left out the widget fields that matched a new object
+// from the palette instead of the zero value, so the decoder keeps the values of a new object for missing fields.
+func migrateV2(doc map[string]interface{}) map[string]interface{} {
+	return doc
+}
+
+// walkNodes calls fn for every object node (a map with a "Type" key) in the JSON tree.
+func walkNodes(data interface{}, fn func(map[string]interface{})) {
+	switch d := data.(type) {
+	case map[string]interface{}:
+		if _, ok := d["Type"].(string); ok {
+			fn(d)
+		}
+		for _, v := range d {
+			walkNodes(v, fn)
+		}
+	case []interface{}:
+		for _, v := range d {
+			walkNodes(v, fn)
+		}
+	}
+}
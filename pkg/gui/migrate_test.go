@@ -0,0 +1,99 @@
+package gui
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/widget"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMigrateDocument(t *testing.T) {
+	doc, err := MigrateDocument(map[string]interface{}{"Type": "*fyne.Container", "Layout": "Max"})
+	require.Nil(t, err)
+	assert.Equal(t, float64(FormatVersion), doc["Version"])
+
+	obj := doc["Object"].(map[string]interface{})
+	assert.Equal(t, "Stack", obj["Layout"])
+
+	_, err = MigrateDocument(map[string]interface{}{"Version": float64(FormatVersion + 1)})
+	assert.NotNil(t, err)
+	_, err = MigrateDocument(map[string]interface{}{"Version": float64(-1)})
+	assert.NotNil(t, err)
+}
+
+func TestMigrateFixtures(t *testing.T) {
+	current, err := os.ReadFile(filepath.Join("testdata", "v3", "container.gui.json"))
+	require.Nil(t, err)
+
+	versions, err := os.ReadDir("testdata")
+	require.Nil(t, err)
+	for _, v := range versions {
+		if !v.IsDir() || !strings.HasPrefix(v.Name(), "v") {
+			continue
+		}
+
+		// versions may also have fixtures of older encoder output, such as v1 with and without default values
+		fixtures, err := filepath.Glob(filepath.Join("testdata", v.Name(), "container*.gui.json"))
+		require.Nil(t, err)
+		for _, fixture := range fixtures {
+			t.Run(v.Name()+"/"+filepath.Base(fixture), func(t *testing.T) {
+				r, err := os.Open(fixture)
+				require.Nil(t, err)
+				defer r.Close()
+
+				obj, meta, err := DecodeObject(r)
+				require.Nil(t, err)
+
+				c, ok := obj.(*fyne.Container)
+				require.True(t, ok)
+				require.Equal(t, 3, len(c.Objects))
+				assert.Equal(t, "content", meta[c]["name"])
+				assert.Equal(t, "Stack", meta[c.Objects[1]]["layout"])
+				_, ok = c.Objects[2].(*container.Scroll)
+				assert.True(t, ok)
+				b := c.Objects[1].(*fyne.Container).Objects[0].(*widget.Button)
+				assert.Equal(t, "Go", b.Text)
+				assert.Equal(t, `func() { println("tapped") }`, meta[b]["OnTapped"])
+
+				var buf bytes.Buffer
+				require.Nil(t, EncodeObject(obj, meta, &buf))
+				assert.Equal(t, string(current), buf.String())
+			})
+		}
+	}
+}
+
+func TestMigrateBorder(t *testing.T) {
+	r, err := os.Open(filepath.Join("testdata", "v1", "border.gui.json"))
+	require.Nil(t, err)
+	defer r.Close()
+
+	obj, meta, err := DecodeObject(r)
+	require.Nil(t, err)
+	c := obj.(*fyne.Container)
+	require.Equal(t, 3, len(c.Objects))
+	title, save := c.Objects[1], c.Objects[2]
+	assert.Equal(t, "node1", meta[title]["id"])
+	assert.Equal(t, "node2", meta[save]["id"])
+	assert.Equal(t, "node1", meta[c]["top"])
+	assert.Equal(t, "node2", meta[c]["bottom"])
+	assert.Equal(t, "", meta[c]["right"])
+
+	c.Resize(fyne.NewSize(200, 200))
+	assert.Equal(t, fyne.NewPos(0, 0), title.Position())
+	assert.Equal(t, float32(200)-save.MinSize().Height, save.Position().Y)
+
+	current, err := os.ReadFile(filepath.Join("testdata", "v3", "border.gui.json"))
+	require.Nil(t, err)
+	var buf bytes.Buffer
+	require.Nil(t, EncodeObject(obj, meta, &buf))
+	assert.Equal(t, string(current), buf.String())
+}
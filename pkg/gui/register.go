@@ -0,0 +1,83 @@
+package gui
+
+import (
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/widget"
+
+	"github.com/fyne-io/defyne/internal/guidefs"
+)
+
+// WidgetInfo describes how the GUI builder creates, edits, encodes and exports a widget type.
+type WidgetInfo struct {
+	// Name is the human readable name shown in the widget palette.
+	Name string
+
+	// Children returns the child objects of a container widget, it should be nil for other widgets.
+	Children func(obj fyne.CanvasObject) []fyne.CanvasObject
+	// AddChild appends a child object to a container widget.
+	// It is also used to restore the children when a design is decoded, so containers should be created empty.
+	AddChild func(parent, child fyne.CanvasObject)
+
+	// Create returns a new instance of the widget with default values.
+	Create func() fyne.CanvasObject
+	// Edit returns the form items that edit the widget, the `refresh` callback can replace the items
+	// and `onchanged` should be called after every change to the widget.
+	Edit func(obj fyne.CanvasObject, props map[string]string, refresh func([]*widget.FormItem), onchanged func()) []*widget.FormItem
+	// EditWithMeta is used instead of Edit if it is set, it is passed the metadata of every object in the design
+	// so that a container can edit properties that refer to its children.
+	EditWithMeta func(obj fyne.CanvasObject, meta map[fyne.CanvasObject]map[string]string, refresh func([]*widget.FormItem),
+		onchanged func()) []*widget.FormItem
+	// Gostring returns the Go code that creates the widget.
+	// The props map contains the metadata for every object in the design.
+	// If the widget is named then the code should be stored in `defs` under that name and "g.<name>" returned instead.
+	Gostring func(obj fyne.CanvasObject, props map[fyne.CanvasObject]map[string]string, defs map[string]string) string
+	// Packages returns the packages that the Go code refers to.
+	// Names like "widget" are Fyne packages, full import paths are used as-is and an alias can be
+	// provided before the path, for example `xwidget "fyne.io/x/fyne/widget"`.
+	Packages func(obj fyne.CanvasObject) []string
+}
+
+// Register adds a widget type to the GUI builder so that it is available in the palette and can be
+// decoded, encoded and exported to Go code.
+// The class is the Go type name of the widget, such as "*xwidget.CompletionEntry".
+// Registering a class that already exists replaces the existing definition.
+// An error is returned if the class or `Name` is empty, or `Create` is missing.
+func Register(class string, info WidgetInfo) error {
+	return guidefs.Register(class, guidefs.WidgetInfo(info))
+}
+
+// Unregister removes a widget type from the GUI builder, such as one added by `Register`.
+func Unregister(class string) {
+	guidefs.Unregister(class)
+}
+
+// LayoutInfo describes how the GUI builder creates, edits and exports a custom container layout,
+// such as one from the project or from fyne-x.
+type LayoutInfo struct {
+	// Create returns the layout for a container, reading the properties of the layout from `meta[c]`.
+	Create func(c *fyne.Container, meta map[fyne.CanvasObject]map[string]string) fyne.Layout
+	// Edit returns the form items that edit the properties of the layout, it may be nil if there are none.
+	// Changes should be stored in `meta[c]` and the container given a new layout from `Create`.
+	Edit func(c *fyne.Container, meta map[fyne.CanvasObject]map[string]string) []*widget.FormItem
+	// Properties maps the properties that the layout stores with the container to a pattern of their valid values,
+	// which is used by the JSON Schema of the design format.
+	Properties map[string]string
+	// Gostring returns the Go code that creates the layout, such as "xlayout.NewResponsiveLayout()".
+	// The container is created by passing the layout and the children to `container.New`.
+	Gostring func(c *fyne.Container, meta map[fyne.CanvasObject]map[string]string) string
+	// Packages returns the packages that the Go code of the layout refers to, in the format of `WidgetInfo.Packages`.
+	Packages func(c *fyne.Container) []string
+}
+
+// RegisterLayout adds a layout to the list that containers can pick from in the GUI builder,
+// so that it can be decoded, encoded and exported to Go code.
+// Registering a name that already exists replaces the existing layout.
+// An error is returned if the name is empty, `Create` or `Gostring` is missing, or a property pattern is invalid.
+func RegisterLayout(name string, info LayoutInfo) error {
+	return guidefs.RegisterLayout(name, guidefs.CustomLayout(info))
+}
+
+// UnregisterLayout removes a layout that was added by `RegisterLayout`.
+func UnregisterLayout(name string) {
+	guidefs.UnregisterLayout(name)
+}
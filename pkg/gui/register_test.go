@@ -0,0 +1,270 @@
+package gui
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"testing"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/widget"
+
+	"github.com/fyne-io/defyne/internal/guidefs"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testBadge struct {
+	widget.BaseWidget
+	Text  string
+	Count int
+}
+
+func newTestBadge(text string, count int) *testBadge {
+	b := &testBadge{Text: text, Count: count}
+	b.ExtendBaseWidget(b)
+	return b
+}
+
+func (b *testBadge) CreateRenderer() fyne.WidgetRenderer {
+	return widget.NewSimpleRenderer(widget.NewLabel(fmt.Sprintf("%s (%d)", b.Text, b.Count)))
+}
+
+type testPanel struct {
+	widget.BaseWidget
+	Title string
+	Items []fyne.CanvasObject `json:"-"`
+}
+
+func (p *testPanel) CreateRenderer() fyne.WidgetRenderer {
+	return widget.NewSimpleRenderer(container.NewVBox(p.Items...))
+}
+
+func registerTestWidgets(t *testing.T) {
+	require.Nil(t, Register("*gui.testBadge", WidgetInfo{
+		Name: "Badge",
+		Create: func() fyne.CanvasObject {
+			return newTestBadge("Badge", 1)
+		},
+		Edit: func(fyne.CanvasObject, map[string]string, func([]*widget.FormItem), func()) []*widget.FormItem {
+			return nil
+		},
+		Gostring: func(obj fyne.CanvasObject, props map[fyne.CanvasObject]map[string]string, defs map[string]string) string {
+			b := obj.(*testBadge)
+			return fmt.Sprintf("badge.New(%q, %d)", b.Text, b.Count)
+		},
+		Packages: func(fyne.CanvasObject) []string {
+			return []string{"example.com/badge"}
+		},
+	}))
+	require.Nil(t, Register("*gui.testPanel", WidgetInfo{
+		Name: "Panel",
+		Children: func(obj fyne.CanvasObject) []fyne.CanvasObject {
+			return obj.(*testPanel).Items
+		},
+		AddChild: func(parent, child fyne.CanvasObject) {
+			p := parent.(*testPanel)
+			p.Items = append(p.Items, child)
+		},
+		Create: func() fyne.CanvasObject {
+			p := &testPanel{}
+			p.ExtendBaseWidget(p)
+			return p
+		},
+	}))
+	t.Cleanup(func() {
+		Unregister("*gui.testBadge")
+		Unregister("*gui.testPanel")
+	})
+}
+
+// testRowLayout places objects in a row at their minimum size, with a gap between them.
+type testRowLayout struct {
+	gap float32
+}
+
+func (r *testRowLayout) Layout(objs []fyne.CanvasObject, _ fyne.Size) {
+	pos := fyne.NewPos(0, 0)
+	for _, o := range objs {
+		o.Resize(o.MinSize())
+		o.Move(pos)
+		pos.X += o.MinSize().Width + r.gap
+	}
+}
+
+func (r *testRowLayout) MinSize(objs []fyne.CanvasObject) fyne.Size {
+	min := fyne.NewSize(0, 0)
+	for i, o := range objs {
+		if i > 0 {
+			min.Width += r.gap
+		}
+		min.Width += o.MinSize().Width
+		min.Height = fyne.Max(min.Height, o.MinSize().Height)
+	}
+	return min
+}
+
+func registerTestLayout(t *testing.T) {
+	require.Nil(t, RegisterLayout("Row", LayoutInfo{
+		Create: func(c *fyne.Container, meta map[fyne.CanvasObject]map[string]string) fyne.Layout {
+			gap, _ := strconv.ParseFloat(meta[c]["gap"], 32)
+			return &testRowLayout{gap: float32(gap)}
+		},
+		Edit: func(c *fyne.Container, meta map[fyne.CanvasObject]map[string]string) []*widget.FormItem {
+			gap := widget.NewEntry()
+			gap.SetText(meta[c]["gap"])
+			gap.OnChanged = func(s string) {
+				meta[c]["gap"] = s
+				gap, _ := strconv.ParseFloat(s, 32)
+				c.Layout = &testRowLayout{gap: float32(gap)}
+				c.Refresh()
+			}
+			return []*widget.FormItem{widget.NewFormItem("Gap", gap)}
+		},
+		Properties: map[string]string{"gap": `^[0-9]*$`},
+		Gostring: func(c *fyne.Container, meta map[fyne.CanvasObject]map[string]string) string {
+			return fmt.Sprintf("rows.NewLayout(%s)", meta[c]["gap"])
+		},
+		Packages: func(*fyne.Container) []string {
+			return []string{"example.com/rows"}
+		},
+	}))
+	t.Cleanup(func() {
+		UnregisterLayout("Row")
+	})
+}
+
+func TestRegister(t *testing.T) {
+	registerTestWidgets(t)
+
+	assert.Contains(t, WidgetClassList(), "*gui.testBadge")
+	assert.Contains(t, ContainerClassList(), "*gui.testPanel")
+	assert.NotContains(t, WidgetClassList(), "*gui.testPanel")
+
+	b, ok := CreateNew("*gui.testBadge").(*testBadge)
+	require.True(t, ok)
+	assert.Equal(t, "Badge", b.Text)
+
+	Unregister("*gui.testBadge")
+	assert.NotContains(t, WidgetClassList(), "*gui.testBadge")
+	assert.Nil(t, CreateNew("*gui.testBadge"))
+}
+
+func TestRegister_Invalid(t *testing.T) {
+	create := func() fyne.CanvasObject {
+		return newTestBadge("Badge", 1)
+	}
+
+	assert.EqualError(t, Register("", WidgetInfo{Name: "Badge", Create: create}), "widget class must not be empty")
+	assert.EqualError(t, Register("*gui.testBadge", WidgetInfo{Create: create}), "widget *gui.testBadge must have a name")
+	assert.EqualError(t, Register("*gui.testBadge", WidgetInfo{Name: "Badge"}), "widget *gui.testBadge must have a Create function")
+	assert.NotContains(t, WidgetClassList(), "*gui.testBadge")
+}
+
+func TestRegister_EncodeDecode(t *testing.T) {
+	registerTestWidgets(t)
+
+	p := CreateNew("*gui.testPanel").(*testPanel)
+	p.Title = "Messages"
+	p.Items = []fyne.CanvasObject{newTestBadge("Inbox", 3), widget.NewLabel("Hi")}
+	meta := map[fyne.CanvasObject]map[string]string{p: {"name": "panel"}}
+
+	var buf bytes.Buffer
+	require.Nil(t, EncodeObject(p, meta, &buf))
+	obj, meta2, err := DecodeObject(&buf)
+	require.Nil(t, err)
+
+	p2, ok := obj.(*testPanel)
+	require.True(t, ok)
+	assert.Equal(t, "Messages", p2.Title)
+	assert.Equal(t, "panel", meta2[p2]["name"])
+	require.Equal(t, 2, len(p2.Items))
+	badge := p2.Items[0].(*testBadge)
+	assert.Equal(t, "Inbox", badge.Text)
+	assert.Equal(t, 3, badge.Count)
+	assert.Equal(t, "Hi", p2.Items[1].(*widget.Label).Text)
+}
+
+func TestRegister_ExportGo(t *testing.T) {
+	registerTestWidgets(t)
+
+	c := container.NewVBox(newTestBadge("Inbox", 3))
+	meta := map[fyne.CanvasObject]map[string]string{c: {"layout": "VBox", "dir": "vertical"}}
+
+	var buf bytes.Buffer
+	require.Nil(t, ExportGo(c, meta, "main", &buf))
+	assert.Contains(t, buf.String(), `"example.com/badge"`)
+	assert.Contains(t, buf.String(), `badge.New("Inbox", 3)`)
+}
+
+func TestRegisterLayout(t *testing.T) {
+	registerTestLayout(t)
+
+	c := container.NewVBox(widget.NewLabel("A"))
+	meta := map[fyne.CanvasObject]map[string]string{c: {"layout": "VBox", "dir": "vertical"}}
+	var items []*widget.FormItem
+	items = EditorForDesign(c, meta, func(i []*widget.FormItem) {
+		items = i
+	}, func() {})
+
+	choose := items[0].Widget.(*widget.Select)
+	assert.Contains(t, choose.Options, "Row")
+	meta[c]["gap"] = "8"
+	choose.SetSelected("Row")
+	require.Equal(t, 2, len(items))
+	assert.Equal(t, "Gap", items[1].Text)
+	assert.Equal(t, "Row", meta[c]["layout"])
+	assert.Equal(t, float32(8), c.Layout.(*testRowLayout).gap)
+}
+
+func TestRegisterLayout_Invalid(t *testing.T) {
+	create := func(*fyne.Container, map[fyne.CanvasObject]map[string]string) fyne.Layout {
+		return &testRowLayout{}
+	}
+	gostring := func(*fyne.Container, map[fyne.CanvasObject]map[string]string) string {
+		return "rows.NewLayout(0)"
+	}
+
+	assert.EqualError(t, RegisterLayout("", LayoutInfo{Create: create, Gostring: gostring}), "layout name must not be empty")
+	assert.EqualError(t, RegisterLayout("Row", LayoutInfo{Gostring: gostring}), "layout Row must have a Create function")
+	assert.EqualError(t, RegisterLayout("Row", LayoutInfo{Create: create}), "layout Row must have a Gostring function")
+	err := RegisterLayout("Row", LayoutInfo{Create: create, Gostring: gostring, Properties: map[string]string{"gap": "[0-9"}})
+	require.NotNil(t, err)
+	assert.Contains(t, err.Error(), "layout Row has an invalid pattern for property gap")
+	assert.NotContains(t, guidefs.CustomLayouts, "Row")
+}
+
+func TestRegisterLayout_EncodeDecode(t *testing.T) {
+	registerTestLayout(t)
+
+	c := container.NewHBox(widget.NewLabel("A"), widget.NewLabel("B"))
+	meta := map[fyne.CanvasObject]map[string]string{c: {"layout": "Row", "gap": "4"}}
+	c.Layout = &testRowLayout{gap: 4}
+
+	var buf bytes.Buffer
+	require.Nil(t, EncodeObject(c, meta, &buf))
+	var node map[string]interface{}
+	require.Nil(t, json.Unmarshal(buf.Bytes(), &node))
+	assert.Equal(t, "Row", node["Object"].(map[string]interface{})["Layout"])
+
+	obj, meta2, err := DecodeObject(&buf)
+	require.Nil(t, err)
+	c2 := obj.(*fyne.Container)
+	assert.Equal(t, "4", meta2[c2]["gap"])
+	assert.Equal(t, float32(4), c2.Layout.(*testRowLayout).gap)
+	assert.Equal(t, 2, len(c2.Objects))
+}
+
+func TestRegisterLayout_ExportGo(t *testing.T) {
+	registerTestLayout(t)
+
+	c := container.NewHBox(widget.NewLabel("A"))
+	meta := map[fyne.CanvasObject]map[string]string{c: {"layout": "Row", "gap": "4"}}
+
+	var buf bytes.Buffer
+	require.Nil(t, ExportGo(c, meta, "main", &buf))
+	assert.Contains(t, buf.String(), `"example.com/rows"`)
+	assert.Contains(t, buf.String(), "container.New(rows.NewLayout(4),\n\t\twidget.NewLabel(\"A\"))")
+}
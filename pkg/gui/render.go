@@ -0,0 +1,38 @@
+package gui
+
+import (
+	"image"
+	"image/color"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/canvas"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/test"
+	"fyne.io/fyne/v2/theme"
+)
+
+// RenderImage draws a design in a window of the given size, using the light or dark variant of the default theme.
+// The design is drawn by the software painter of the Fyne test driver, so no display or graphics driver is needed.
+// A test app replaces the current app, so this should not be called from a running GUI.
+// The theme overrides the design instead of the app, as the app applies a new theme in the background.
+func RenderImage(obj fyne.CanvasObject, size fyne.Size, variant fyne.ThemeVariant) image.Image {
+	test.NewApp()
+	th := &variantTheme{Theme: theme.DefaultTheme(), variant: variant}
+	bg := canvas.NewRectangle(th.Color(theme.ColorNameBackground, variant))
+
+	w := test.NewWindow(container.NewThemeOverride(container.NewStack(bg, container.NewPadded(obj)), th))
+	w.SetPadded(false)
+	defer w.Close()
+	w.Resize(size)
+	return w.Canvas().Capture()
+}
+
+// variantTheme shows a theme in one variant, whatever the variant of the system.
+type variantTheme struct {
+	fyne.Theme
+	variant fyne.ThemeVariant
+}
+
+func (t *variantTheme) Color(n fyne.ThemeColorName, _ fyne.ThemeVariant) color.Color {
+	return t.Theme.Color(n, t.variant)
+}
@@ -0,0 +1,35 @@
+package gui
+
+import (
+	"testing"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRenderImage(t *testing.T) {
+	light := RenderImage(widget.NewLabel("Hello"), fyne.NewSize(120, 80), theme.VariantLight)
+	assert.Equal(t, 120, light.Bounds().Dx())
+	assert.Equal(t, 80, light.Bounds().Dy())
+	assert.Equal(t, toNRGBA(theme.DefaultTheme().Color(theme.ColorNameBackground, theme.VariantLight)),
+		toNRGBA(light.At(119, 79)))
+
+	dark := RenderImage(widget.NewLabel("Hello"), fyne.NewSize(120, 80), theme.VariantDark)
+	assert.Equal(t, toNRGBA(theme.DefaultTheme().Color(theme.ColorNameBackground, theme.VariantDark)),
+		toNRGBA(dark.At(119, 79)))
+}
+
+func TestParseSize(t *testing.T) {
+	size, err := ParseSize("800x600.5")
+	require.Nil(t, err)
+	assert.Equal(t, fyne.NewSize(800, 600.5), size)
+
+	for _, s := range []string{"", "800", "wide x600", "800xtall"} {
+		_, err = ParseSize(s)
+		assert.NotNil(t, err, s)
+	}
+}
@@ -0,0 +1,13 @@
+package gui
+
+import (
+	"fyne.io/fyne/v2"
+
+	"github.com/fyne-io/defyne/internal/guidefs"
+)
+
+// SetProjectRoot sets the directory that project resources, such as "assets/logo.svg", are relative to.
+// If it is not set then resource paths are relative to the current working directory.
+func SetProjectRoot(dir fyne.URI) {
+	guidefs.ProjectRoot = dir
+}
@@ -0,0 +1,96 @@
+package gui
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/storage"
+	"fyne.io/fyne/v2/widget"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const logoSVG = `<svg xmlns="http://www.w3.org/2000/svg" viewBox="0 0 24 24"><circle cx="12" cy="12" r="10"/></svg>`
+
+const resourceJSON = `{
+  "Version": 1,
+  "Object": {
+    "Type": "*widget.Icon",
+    "Struct": {
+      "Resource": "assets/logo.svg"
+    }
+  }
+}`
+
+func setupProject(t *testing.T) {
+	dir := t.TempDir()
+	require.Nil(t, os.Mkdir(filepath.Join(dir, "assets"), 0755))
+	require.Nil(t, os.WriteFile(filepath.Join(dir, "assets", "logo.svg"), []byte(logoSVG), 0644))
+
+	SetProjectRoot(storage.NewFileURI(dir))
+	t.Cleanup(func() {
+		SetProjectRoot(nil)
+	})
+}
+
+func TestProjectResource_EncodeDecode(t *testing.T) {
+	setupProject(t)
+
+	obj, meta, err := DecodeObject(strings.NewReader(resourceJSON))
+	require.Nil(t, err)
+	icon := obj.(*widget.Icon)
+	require.NotNil(t, icon.Resource)
+	assert.Equal(t, logoSVG, string(icon.Resource.Content()))
+
+	var buf bytes.Buffer
+	require.Nil(t, EncodeObject(obj, meta, &buf))
+	assert.Contains(t, buf.String(), `"Resource": "assets/logo.svg"`)
+
+	_, _, err = DecodeObject(strings.NewReader(strings.Replace(resourceJSON, "logo.svg", "missing.svg", 1)))
+	require.NotNil(t, err)
+	assert.True(t, strings.HasPrefix(err.Error(), `Struct.Resource: failed to load resource "assets/missing.svg"`))
+}
+
+func TestProjectResource_OutsideProject(t *testing.T) {
+	setupProject(t)
+
+	for _, name := range []string{"../logo.svg", "assets/../../logo.svg", "/tmp/logo.svg"} {
+		_, _, err := DecodeObject(strings.NewReader(strings.Replace(resourceJSON, "assets/logo.svg", name, 1)))
+		require.NotNil(t, err, name)
+		assert.Contains(t, err.Error(), "must", name)
+	}
+
+	_, _, err := DecodeObject(strings.NewReader(strings.Replace(resourceJSON, "assets/logo.svg", "Account.Icon", 1)))
+	require.NotNil(t, err)
+	assert.Equal(t, `Struct.Resource: unknown icon "Account.Icon"`, err.Error())
+}
+
+func TestProjectResource_ExportGo(t *testing.T) {
+	setupProject(t)
+
+	obj, _, err := DecodeObject(strings.NewReader(resourceJSON))
+	require.Nil(t, err)
+	c := container.NewVBox(obj)
+	meta := map[fyne.CanvasObject]map[string]string{c: {"layout": "VBox", "dir": "vertical"}}
+
+	var buf bytes.Buffer
+	require.Nil(t, ExportGo(c, meta, "main", &buf))
+	code := buf.String()
+	assert.Contains(t, code, `widget.NewIcon(g.loadResource("assets/logo.svg"))`)
+	assert.Contains(t, code, "func (g *gui) loadResource(path string) fyne.Resource {")
+	assert.Contains(t, code, `case "assets/logo.svg":`)
+	assert.Contains(t, code, `StaticName: "logo.svg"`)
+	assert.NotContains(t, code, "fyne.LoadResourceFromPath")
+	assert.Contains(t, code, `"fyne.io/fyne/v2/theme"`)
+
+	imported, _, err := ImportGo(&buf, "")
+	require.Nil(t, err)
+	icon := imported.(*fyne.Container).Objects[0].(*widget.Icon)
+	assert.Equal(t, logoSVG, string(icon.Resource.Content()))
+}
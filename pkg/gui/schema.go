@@ -0,0 +1,427 @@
+package gui
+
+import (
+	"encoding/json"
+	"image/color"
+	"io"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/widget"
+
+	"github.com/fyne-io/defyne/internal/guidefs"
+)
+
+// SchemaID is the identifier of the JSON Schema written by `ExportSchema`.
+const SchemaID = "https://github.com/fyne-io/defyne/gui.schema.json"
+
+var (
+	colorValueType = reflect.TypeOf((*color.Color)(nil)).Elem()
+	timeType       = reflect.TypeOf(time.Time{})
+)
+
+// ExportSchema writes a JSON Schema for the .gui.json format to the provided file handle.
+// The schema is generated from the registered classes, including those added with `Register`,
+// and the known layouts so that files can be validated without loading them.
+func ExportSchema(w io.Writer) error {
+	guidefs.InitOnce()
+
+	s := &schemaBuilder{defs: make(map[string]interface{})}
+	s.defs["object"] = s.objectSchema()
+	s.defs["resource"] = map[string]interface{}{
+		"type":        "string",
+		"description": "The name of a theme icon or the path of a file in the project",
+	}
+	s.defs["color"] = map[string]interface{}{
+		"type": "object",
+		"properties": map[string]interface{}{
+			"R": integerSchema(255),
+			"G": integerSchema(255),
+			"B": integerSchema(255),
+			"A": integerSchema(255),
+			"Y": integerSchema(65535),
+		},
+	}
+
+	schema := map[string]interface{}{
+		"$schema": "https://json-schema.org/draft/2020-12/schema",
+		"$id":     SchemaID,
+		"title":   "Fyne GUI design",
+		"type":    "object",
+		"properties": map[string]interface{}{
+			"Version":  map[string]interface{}{"const": FormatVersion},
+			"TestSize": s.typeSchema(reflect.TypeOf(fyne.Size{})),
+			"Dialog":   dialogSchema(),
+			"Window":   s.windowSchema(),
+			"Handlers": map[string]interface{}{"type": "boolean"},
+			"Object":   ref("object"),
+		},
+		"required":             []string{"Version", "Object"},
+		"additionalProperties": false,
+		"$defs":                s.defs,
+	}
+
+	e := json.NewEncoder(w)
+	e.SetIndent("", "  ")
+	return e.Encode(schema)
+}
+
+type schemaBuilder struct {
+	defs map[string]interface{}
+}
+
+// objectSchema returns the schema of an object node, which picks the definition of its class from the "Type" field.
+func (s *schemaBuilder) objectSchema() interface{} {
+	classes := schemaClasses()
+	rules := make([]interface{}, 0, len(classes)+1)
+	for _, class := range classes {
+		s.defs[class] = s.classSchema(class)
+		rules = append(rules, typeRule(class))
+	}
+
+	s.defs[includeType] = map[string]interface{}{
+		"type": "object",
+		"properties": map[string]interface{}{
+			"Type": map[string]interface{}{"const": includeType},
+			"Name": stringSchema(),
+			"ID":   idSchema(),
+			"Src":  map[string]interface{}{"type": "string", "description": "The path of the included design"},
+		},
+		"required":             []string{"Type", "Src"},
+		"additionalProperties": false,
+	}
+	rules = append(rules, typeRule(includeType))
+
+	return map[string]interface{}{
+		"type": "object",
+		"properties": map[string]interface{}{
+			"Type": map[string]interface{}{"enum": append(classes, includeType)},
+		},
+		"required": []string{"Type"},
+		"allOf":    rules,
+	}
+}
+
+func (s *schemaBuilder) classSchema(class string) interface{} {
+	props := map[string]interface{}{
+		"Type": map[string]interface{}{"const": class},
+		"Name": stringSchema(),
+		"ID":   idSchema(),
+	}
+	node := map[string]interface{}{
+		"type":                 "object",
+		"properties":           props,
+		"required":             []string{"Type", "Struct"},
+		"additionalProperties": false,
+	}
+
+	switch class {
+	case "*fyne.Container":
+		return s.containerSchema()
+	case "*container.AppTabs":
+		props["Struct"] = strictObject(map[string]interface{}{
+			"Items": arraySchema(map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"Text":    stringSchema(),
+					"Icon":    ref("resource"),
+					"Content": ref("object"),
+				},
+				"required": []string{"Content"},
+			}),
+			"SelectedIndex": map[string]interface{}{"type": "integer"},
+		})
+		return node
+	case "*container.Scroll", "*widget.Scroll":
+		props["Struct"] = strictObject(map[string]interface{}{
+			"Direction": map[string]interface{}{"type": "integer"},
+			"Content":   nullable(ref("object")),
+		})
+		return node
+	case "*container.Split":
+		props["Struct"] = strictObject(map[string]interface{}{
+			"Horizontal": map[string]interface{}{"type": "boolean"},
+			"Offset":     map[string]interface{}{"type": "number"},
+			"Leading":    nullable(ref("object")),
+			"Trailing":   nullable(ref("object")),
+		})
+		return node
+	}
+
+	obj := guidefs.Lookup(class).Create()
+	t := reflect.TypeOf(obj).Elem()
+	props["Struct"] = s.typeSchema(t)
+	if colors := guidefs.ColorFields(obj); len(colors) > 0 {
+		props["ThemeColors"] = stringFields(colors, false)
+	}
+	if _, graphic := guidefs.Graphics[class]; graphic {
+		return node
+	}
+
+	var actions []string
+	for _, f := range reflect.VisibleFields(t) {
+		if f.IsExported() && f.Type.Kind() == reflect.Func && strings.HasPrefix(f.Name, "On") {
+			actions = append(actions, f.Name)
+		}
+	}
+	props["Actions"] = stringFields(actions, true)
+	if fields := guidefs.TranslatableFields[class]; len(fields) > 0 {
+		props["Translations"] = stringFields(fields, false)
+	}
+	if guidefs.Bindings[class].Type != "" {
+		props["Binding"] = stringSchema()
+	}
+	if guidefs.Lookup(class).IsContainer() {
+		props["Objects"] = arraySchema(ref("object"))
+	}
+	return node
+}
+
+// containerSchema returns the schema of a container, checking the properties that its layout reads.
+func (s *schemaBuilder) containerSchema() interface{} {
+	names := make([]string, 0, len(guidefs.Layouts))
+	for name := range guidefs.Layouts {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var rules []interface{}
+	for _, name := range names {
+		props := guidefs.LayoutProperties[name]
+		if len(props) == 0 {
+			continue
+		}
+
+		fields := make(map[string]interface{}, len(props))
+		for key, pattern := range props {
+			fields[key] = map[string]interface{}{"type": "string", "pattern": pattern}
+		}
+		rules = append(rules, map[string]interface{}{
+			"if": map[string]interface{}{
+				"properties": map[string]interface{}{"Layout": map[string]interface{}{"const": name}},
+			},
+			"then": map[string]interface{}{
+				"properties": map[string]interface{}{"Properties": map[string]interface{}{"properties": fields}},
+			},
+		})
+	}
+
+	return map[string]interface{}{
+		"type": "object",
+		"properties": map[string]interface{}{
+			"Type":    map[string]interface{}{"const": "*fyne.Container"},
+			"Name":    stringSchema(),
+			"ID":      idSchema(),
+			"Layout":  map[string]interface{}{"enum": names},
+			"Objects": nullable(arraySchema(nullable(ref("object")))),
+			"Properties": map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"layout": map[string]interface{}{"enum": names},
+					"name":   stringSchema(),
+				},
+				"additionalProperties": stringSchema(),
+			},
+		},
+		"required":             []string{"Type", "Layout"},
+		"additionalProperties": false,
+		"allOf":                rules,
+	}
+}
+
+// typeSchema returns the schema for a field of the given type, following the rules of `decodeFields`.
+// Struct types are added to the definitions so that recursive types can be described.
+func (s *schemaBuilder) typeSchema(t reflect.Type) interface{} {
+	switch t {
+	case resourceType:
+		return ref("resource")
+	case colorValueType:
+		return ref("color")
+	case canvasObjectType:
+		return map[string]interface{}{"description": "Nested content is not stored in the object struct"}
+	case timeType:
+		return map[string]interface{}{"type": "string", "format": "date-time"}
+	case toolbarItemType:
+		return map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"Type": map[string]interface{}{"enum": []string{"Separator", "Spacer"}},
+				"Icon": ref("resource"),
+			},
+		}
+	case reflect.TypeOf((*widget.AccordionItem)(nil)):
+		return map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"Title":  stringSchema(),
+				"Open":   map[string]interface{}{"type": "boolean"},
+				"Detail": ref("object"),
+			},
+		}
+	case reflect.TypeOf((*widget.FormItem)(nil)):
+		return map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"HintText": stringSchema(),
+				"Text":     stringSchema(),
+				"Widget":   ref("object"),
+			},
+		}
+	case reflect.TypeOf((*widget.RichTextSegment)(nil)).Elem():
+		return s.typeSchema(reflect.TypeOf(widget.TextSegment{}))
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		return map[string]interface{}{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return map[string]interface{}{"type": "integer"}
+	case reflect.Float32, reflect.Float64:
+		return map[string]interface{}{"type": "number"}
+	case reflect.String:
+		return stringSchema()
+	case reflect.Ptr:
+		return s.typeSchema(t.Elem())
+	case reflect.Slice, reflect.Array:
+		return arraySchema(s.typeSchema(t.Elem()))
+	case reflect.Map:
+		return map[string]interface{}{"type": "object"}
+	case reflect.Struct:
+		name := t.String()
+		if _, ok := s.defs[name]; !ok {
+			s.defs[name] = nil // reserve the name while the fields are described
+			s.defs[name] = s.structSchema(t)
+		}
+		return ref(name)
+	}
+
+	return map[string]interface{}{}
+}
+
+// structSchema describes the fields written for a struct, any other field name is rejected by the decoder.
+func (s *schemaBuilder) structSchema(t reflect.Type) interface{} {
+	props := make(map[string]interface{})
+	for _, f := range structFields(t) {
+		ft := t.FieldByIndex(f.index).Type
+		if ft.Kind() == reflect.Func || ft.Kind() == reflect.Chan {
+			continue
+		}
+		props[f.name] = nullable(s.typeSchema(ft))
+	}
+
+	return strictObject(props)
+}
+
+// schemaClasses returns the names of all registered classes, sorted.
+// Includes are written as their own node type and are described separately.
+func schemaClasses() []string {
+	var classes []string
+	for _, list := range [][]string{WidgetClassList(), CollectionClassList(), ContainerClassList(), GraphicsClassList()} {
+		for _, class := range list {
+			if _, ok := guidefs.Lookup(class).Create().(*guidefs.Include); !ok {
+				classes = append(classes, class)
+			}
+		}
+	}
+
+	sort.Strings(classes)
+	return classes
+}
+
+func arraySchema(items interface{}) interface{} {
+	return map[string]interface{}{"type": "array", "items": items}
+}
+
+func integerSchema(max int) interface{} {
+	return map[string]interface{}{"type": "integer", "minimum": 0, "maximum": max}
+}
+
+func nullable(schema interface{}) interface{} {
+	return map[string]interface{}{"anyOf": []interface{}{schema, map[string]interface{}{"type": "null"}}}
+}
+
+func ref(name string) interface{} {
+	return map[string]interface{}{"$ref": "#/$defs/" + name}
+}
+
+// stringFields returns an object schema for the named string fields, optionally allowing other fields.
+func stringFields(names []string, others bool) interface{} {
+	props := make(map[string]interface{}, len(names))
+	for _, name := range names {
+		props[name] = stringSchema()
+	}
+
+	schema := map[string]interface{}{"type": "object", "properties": props}
+	if others {
+		schema["additionalProperties"] = stringSchema()
+	} else {
+		schema["additionalProperties"] = false
+	}
+	return schema
+}
+
+// dialogSchema returns the schema of the dialog that a design is shown in.
+func dialogSchema() interface{} {
+	return map[string]interface{}{
+		"type": "object",
+		"properties": map[string]interface{}{
+			"Type":    map[string]interface{}{"enum": DialogTypes},
+			"Title":   stringSchema(),
+			"Dismiss": stringSchema(),
+			"Confirm": stringSchema(),
+			"Actions": stringFields(dialogActions, false),
+		},
+		"required":             []string{"Type"},
+		"additionalProperties": false,
+	}
+}
+
+// windowSchema returns the schema of the window that a design is shown in, menu items may hold a submenu of items.
+func (s *schemaBuilder) windowSchema() interface{} {
+	s.defs["menuItem"] = strictObject(map[string]interface{}{
+		"Label":     stringSchema(),
+		"Separator": map[string]interface{}{"type": "boolean"},
+		"Shortcut": map[string]interface{}{"type": "string", "pattern": "^((Shortcut|Control|Alt|Shift|Super)\\+)+[^+]+$",
+			"description": "Modifiers and a key name, such as Shortcut+Shift+S"},
+		"Action": stringSchema(),
+		"Items":  arraySchema(ref("menuItem")),
+	})
+
+	return strictObject(map[string]interface{}{
+		"Title":     stringSchema(),
+		"Size":      s.typeSchema(reflect.TypeOf(fyne.Size{})),
+		"FixedSize": map[string]interface{}{"type": "boolean"},
+		"Master":    map[string]interface{}{"type": "boolean"},
+		"MainMenu": arraySchema(strictObject(map[string]interface{}{
+			"Label": stringSchema(),
+			"Items": arraySchema(ref("menuItem")),
+		})),
+	})
+}
+
+// idSchema returns the schema of a node ID, which the properties of a container use to refer to a child.
+func idSchema() interface{} {
+	return map[string]interface{}{"type": "string", "pattern": guidefs.NodeIDPattern,
+		"description": "An ID that the properties of a container use to refer to this object"}
+}
+
+func stringSchema() interface{} {
+	return map[string]interface{}{"type": "string"}
+}
+
+func strictObject(props map[string]interface{}) interface{} {
+	return map[string]interface{}{"type": "object", "properties": props, "additionalProperties": false}
+}
+
+func typeRule(class string) interface{} {
+	return map[string]interface{}{
+		"if": map[string]interface{}{
+			"properties": map[string]interface{}{"Type": map[string]interface{}{"const": class}},
+		},
+		"then": ref(class),
+	}
+}
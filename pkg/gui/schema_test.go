@@ -0,0 +1,264 @@
+package gui
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/widget"
+
+	"github.com/fyne-io/defyne/internal/guidefs"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func loadSchema(t *testing.T) map[string]interface{} {
+	var buf bytes.Buffer
+	require.Nil(t, ExportSchema(&buf))
+
+	var schema map[string]interface{}
+	require.Nil(t, json.Unmarshal(buf.Bytes(), &schema))
+	return schema
+}
+
+func TestExportSchema(t *testing.T) {
+	schema := loadSchema(t)
+	assert.Equal(t, SchemaID, schema["$id"])
+
+	defs := schema["$defs"].(map[string]interface{})
+	for _, class := range conformanceClasses() {
+		if _, ok := CreateNew(class).(*guidefs.Include); ok {
+			continue
+		}
+		assert.Contains(t, defs, class)
+	}
+	assert.Contains(t, defs, "include")
+
+	var first, second bytes.Buffer
+	require.Nil(t, ExportSchema(&first))
+	require.Nil(t, ExportSchema(&second))
+	assert.Equal(t, first.String(), second.String())
+}
+
+func TestExportSchema_Valid(t *testing.T) {
+	schema := loadSchema(t)
+
+	for _, class := range conformanceClasses() {
+		obj := CreateNew(class)
+		var buf bytes.Buffer
+		require.Nil(t, EncodeObject(obj, conformanceMeta(obj), &buf))
+		assert.Empty(t, validateSchema(schema, decodeJSON(t, buf.Bytes())), class)
+	}
+
+	obj, meta := translatedDesign()
+	SetTestSize(obj, meta, fyne.NewSize(640, 480))
+	var buf bytes.Buffer
+	require.Nil(t, EncodeObject(obj, meta, &buf))
+	assert.Empty(t, validateSchema(schema, decodeJSON(t, buf.Bytes())))
+
+	grid := container.NewGridWithColumns(3, widget.NewButton("Tap", nil))
+	buf.Reset()
+	require.Nil(t, EncodeObject(grid, map[fyne.CanvasObject]map[string]string{
+		grid: {"layout": "Grid", "grid_type": "Columns", "count": "3"}}, &buf))
+	assert.Empty(t, validateSchema(schema, decodeJSON(t, buf.Bytes())))
+
+	files, _ := filepath.Glob(filepath.Join("testdata", "v3", "*.gui.json"))
+	require.NotEmpty(t, files)
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		require.Nil(t, err)
+		assert.Empty(t, validateSchema(schema, decodeJSON(t, data)), file)
+	}
+
+	assert.Empty(t, validateSchema(schema, decodeJSON(t, []byte(
+		`{"Version": 3, "Object": {"Type": "include", "Name": "header", "Src": "header.gui.json"}}`))))
+}
+
+func TestExportSchema_Invalid(t *testing.T) {
+	schema := loadSchema(t)
+
+	for doc, problem := range map[string]string{
+		`{"Object": {"Type": "*widget.Separator", "Struct": {}}}`:                                                  "missing property Version",
+		`{"Version": 3, "Object": {"Type": "*widget.Unknown", "Struct": {}}}`:                                      "/Object/Type: value not in enum",
+		`{"Version": 3, "Object": {"Type": "*widget.Label", "Struct": {"Txt": "x"}}}`:                              "/Object/Struct: unexpected property Txt",
+		`{"Version": 3, "Object": {"Type": "*widget.Label", "Struct": {"Text": 5}}}`:                               "/Object/Struct/Text: expected string",
+		`{"Version": 3, "Object": {"Type": "*widget.Label", "Binding": 5, "Struct": {}}}`:                          "/Object/Binding: expected string",
+		`{"Version": 3, "Object": {"Type": "*widget.Separator", "Binding": "x", "Struct": {}}}`:                    "/Object: unexpected property Binding",
+		`{"Version": 3, "Object": {"Type": "*fyne.Container", "Layout": "Diagonal"}}`:                              "/Object/Layout: value not in enum",
+		`{"Version": 3, "Object": {"Type": "*fyne.Container", "Layout": "Grid", "Properties": {"count": "many"}}}`: "/Object/Properties/count: does not match",
+		`{"Version": 3, "Object": {"Type": "include"}}`:                                                            "/Object: missing property Src",
+		`{"Version": 3, "Object": {"Type": "*widget.Label", "ID": "1 label", "Struct": {}}}`:                       "/Object/ID: does not match",
+		`{"Version": 3, "Object": {"Type": "*canvas.Rectangle", "Struct": {"FillColor": {"R": -1}}}}`:              "/Object/Struct/FillColor/R: smaller than 0",
+	} {
+		problems := validateSchema(schema, decodeJSON(t, []byte(doc)))
+		assert.NotEmpty(t, problems, doc)
+		assert.Contains(t, strings.Join(problems, "\n"), problem, doc)
+	}
+}
+
+func TestValidateSchema_UnsupportedKeyword(t *testing.T) {
+	schema := map[string]interface{}{
+		"$defs":      map[string]interface{}{},
+		"type":       "object",
+		"properties": map[string]interface{}{"Name": map[string]interface{}{"type": "string", "minLength": 1}},
+	}
+
+	problems := validateSchema(schema, decodeJSON(t, []byte(`{"Name": "x"}`)))
+	assert.Equal(t, []string{"/Name: unsupported schema keyword minLength"}, problems)
+}
+
+func decodeJSON(t *testing.T, data []byte) interface{} {
+	var v interface{}
+	require.Nil(t, json.Unmarshal(data, &v))
+	return v
+}
+
+// validateSchema checks a value against the subset of JSON Schema used by `ExportSchema` and returns the problems.
+// Any keyword outside that subset is reported as a problem, so the schema cannot silently outgrow the checks.
+func validateSchema(schema map[string]interface{}, v interface{}) []string {
+	return schemaValidator{schema["$defs"].(map[string]interface{})}.validate(schema, v, "")
+}
+
+// schemaKeywords lists the keywords that schemaValidator checks, or can safely ignore as annotations.
+var schemaKeywords = map[string]bool{
+	"$schema": true, "$id": true, "$defs": true, "title": true, "description": true,
+	"$ref": true, "type": true, "const": true, "enum": true, "pattern": true, "format": true,
+	"minimum": true, "maximum": true, "anyOf": true, "allOf": true, "if": true, "then": true,
+	"items": true, "required": true, "properties": true, "additionalProperties": true,
+}
+
+type schemaValidator struct {
+	defs map[string]interface{}
+}
+
+func (s schemaValidator) validate(schema map[string]interface{}, v interface{}, path string) (problems []string) {
+	fail := func(format string, args ...interface{}) {
+		problems = append(problems, path+": "+fmt.Sprintf(format, args...))
+	}
+
+	for key := range schema {
+		if !schemaKeywords[key] {
+			fail("unsupported schema keyword %s", key)
+		}
+	}
+	if typ, ok := schema["type"]; ok {
+		if _, ok := typ.(string); !ok {
+			fail("unsupported schema type %v", typ)
+		}
+	}
+	if format, ok := schema["format"]; ok && format != "date-time" {
+		fail("unsupported schema format %v", format)
+	}
+	if len(problems) > 0 {
+		return problems
+	}
+
+	if r, ok := schema["$ref"].(string); ok {
+		return s.validate(s.defs[strings.TrimPrefix(r, "#/$defs/")].(map[string]interface{}), v, path)
+	}
+	if typ, ok := schema["type"].(string); ok && jsonSchemaType(v) != typ &&
+		!(typ == "number" && jsonSchemaType(v) == "integer") {
+		fail("expected %s", typ)
+		return problems
+	}
+	if c, ok := schema["const"]; ok && fmt.Sprint(c) != fmt.Sprint(v) {
+		fail("expected %v", c)
+	}
+	if enum, ok := schema["enum"].([]interface{}); ok {
+		found := false
+		for _, e := range enum {
+			found = found || e == v
+		}
+		if !found {
+			fail("value not in enum")
+		}
+	}
+	if str, ok := v.(string); ok {
+		if pattern, ok := schema["pattern"].(string); ok && !regexp.MustCompile(pattern).MatchString(str) {
+			fail("does not match %s", pattern)
+		}
+		if _, ok := schema["format"]; ok {
+			if _, err := time.Parse(time.RFC3339, str); err != nil {
+				fail("not a date-time")
+			}
+		}
+	}
+	if num, ok := v.(float64); ok {
+		if min, ok := schema["minimum"].(float64); ok && num < min {
+			fail("smaller than %g", min)
+		}
+		if max, ok := schema["maximum"].(float64); ok && num > max {
+			fail("larger than %g", max)
+		}
+	}
+	if anyOf, ok := schema["anyOf"].([]interface{}); ok {
+		var nested []string
+		for _, option := range anyOf {
+			found := s.validate(option.(map[string]interface{}), v, path)
+			if len(found) == 0 {
+				nested = nil
+				break
+			}
+			nested = append(nested, found...)
+		}
+		problems = append(problems, nested...)
+	}
+	if allOf, ok := schema["allOf"].([]interface{}); ok {
+		for _, rule := range allOf {
+			problems = append(problems, s.validate(rule.(map[string]interface{}), v, path)...)
+		}
+	}
+	if cond, ok := schema["if"].(map[string]interface{}); ok && len(s.validate(cond, v, path)) == 0 {
+		problems = append(problems, s.validate(schema["then"].(map[string]interface{}), v, path)...)
+	}
+
+	if items, ok := schema["items"].(map[string]interface{}); ok {
+		list, _ := v.([]interface{})
+		for i, item := range list {
+			problems = append(problems, s.validate(items, item, fmt.Sprintf("%s/%d", path, i))...)
+		}
+	}
+	m, ok := v.(map[string]interface{})
+	if !ok {
+		return problems
+	}
+	if required, ok := schema["required"].([]interface{}); ok {
+		for _, key := range required {
+			if _, ok := m[key.(string)]; !ok {
+				fail("missing property %s", key)
+			}
+		}
+	}
+	props, _ := schema["properties"].(map[string]interface{})
+	for key, val := range m {
+		if prop, ok := props[key]; ok {
+			problems = append(problems, s.validate(prop.(map[string]interface{}), val, path+"/"+key)...)
+			continue
+		}
+
+		switch extra := schema["additionalProperties"].(type) {
+		case bool:
+			if !extra {
+				fail("unexpected property %s", key)
+			}
+		case map[string]interface{}:
+			problems = append(problems, s.validate(extra, val, path+"/"+key)...)
+		}
+	}
+	return problems
+}
+
+func jsonSchemaType(v interface{}) string {
+	if n, ok := v.(float64); ok && n == float64(int64(n)) {
+		return "integer"
+	}
+	return jsonType(v)
+}
@@ -0,0 +1,129 @@
+package gui
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"fyne.io/fyne/v2"
+
+	"github.com/fyne-io/defyne/internal/guidefs"
+)
+
+// testSizeKey stores the size that the generated test renders a design at, in the metadata of the root object.
+const testSizeKey = "test_size"
+
+var defaultTestSize = fyne.NewSize(400, 300)
+
+// TestSize returns the size that the test generated by `ExportGoTest` renders the design at.
+func TestSize(obj fyne.CanvasObject, meta map[fyne.CanvasObject]map[string]string) fyne.Size {
+	if size, ok := testSize(meta[obj]); ok {
+		return size
+	}
+
+	return defaultTestSize
+}
+
+// SetTestSize sets the size that the test generated by `ExportGoTest` renders the design at.
+// The size is stored in the metadata of the root object.
+func SetTestSize(obj fyne.CanvasObject, meta map[fyne.CanvasObject]map[string]string, size fyne.Size) {
+	props, ok := meta[obj]
+	if !ok {
+		props = make(map[string]string)
+		meta[obj] = props
+	}
+
+	props[testSizeKey] = strconv.FormatFloat(float64(size.Width), 'f', -1, 32) + "x" +
+		strconv.FormatFloat(float64(size.Height), 'f', -1, 32)
+}
+
+// TestSnapshotFile returns the name of the markup file, within the "testdata" directory,
+// that the generated test compares the named design against.
+func TestSnapshotFile(name string) string {
+	return name + ".gui.xml"
+}
+
+// SnapshotUpdateEnv is the environment variable that makes the tests written by `ExportGoTest` record their snapshots,
+// for example `DEFYNE_UPDATE_SNAPSHOTS=1 go test`.
+const SnapshotUpdateEnv = "DEFYNE_UPDATE_SNAPSHOTS"
+
+// ExportGoTest generates a Go test for the code written by `ExportGo` and writes it to the provided file handle.
+// The test renders the design with the Fyne test package at its `TestSize` and compares the markup with a snapshot
+// in the "testdata" directory. A missing snapshot fails the test, it is only recorded if `SnapshotUpdateEnv` is set.
+func ExportGoTest(obj fyne.CanvasObject, meta map[fyne.CanvasObject]map[string]string, name string, w io.Writer) error {
+	guidefs.InitOnce()
+
+	testName := "TestGUI"
+	if name != "main" {
+		testName = "Test" + strings.ToUpper(name[0:1]) + name[1:] + "GUI"
+	}
+	_, create := guidefs.IncludeGoNames(name + ".gui.json")
+	size := TestSize(obj, meta)
+	snapshot := strconv.Quote(TestSnapshotFile(name))
+
+	code := fmt.Sprintf(`// auto-generated
+// Code generated by GUI builder.
+
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/test"
+)
+
+// %s checks that the design renders the same markup as its snapshot in testdata.
+// Run the test with %s=1 set to record the snapshot after the design is changed.
+func %s(t *testing.T) {
+	test.NewTempApp(t)
+	w := test.NewTempWindow(t, %s.makeUI())
+	w.Resize(fyne.NewSize(%s, %s))
+
+	snapshot := filepath.Join("testdata", %s)
+	if os.Getenv(%q) != "" {
+		err := os.MkdirAll("testdata", 0755)
+		if err == nil {
+			err = os.WriteFile(snapshot, []byte(test.RenderToMarkup(w.Canvas())), 0644)
+		}
+		if err != nil {
+			t.Fatal("Failed to record snapshot", err)
+		}
+	} else if _, err := os.Stat(snapshot); err != nil {
+		t.Fatalf("Missing snapshot %%s, run the test with %s=1 set to record it", snapshot)
+	}
+	test.AssertRendersToMarkup(t, %s, w.Canvas())
+}
+`, testName, SnapshotUpdateEnv, testName, create,
+		strconv.FormatFloat(float64(size.Width), 'f', -1, 32), strconv.FormatFloat(float64(size.Height), 'f', -1, 32),
+		snapshot, SnapshotUpdateEnv, SnapshotUpdateEnv, snapshot)
+
+	_, err := w.Write([]byte(code))
+	return err
+}
+
+// ParseSize returns the size described by a string like "800x600".
+func ParseSize(s string) (fyne.Size, error) {
+	w, h, ok := strings.Cut(s, "x")
+	if !ok {
+		return fyne.Size{}, fmt.Errorf("size %q must be like 800x600", s)
+	}
+
+	width, err := strconv.ParseFloat(w, 32)
+	if err != nil {
+		return fyne.Size{}, fmt.Errorf("invalid width in size %q", s)
+	}
+	height, err := strconv.ParseFloat(h, 32)
+	if err != nil {
+		return fyne.Size{}, fmt.Errorf("invalid height in size %q", s)
+	}
+	return fyne.NewSize(float32(width), float32(height)), nil
+}
+
+func testSize(props map[string]string) (fyne.Size, bool) {
+	size, err := ParseSize(props[testSizeKey])
+	return size, err == nil
+}
@@ -0,0 +1,60 @@
+package gui
+
+import (
+	"bytes"
+	"go/parser"
+	"go/token"
+	"strings"
+	"testing"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/widget"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExportGoTest(t *testing.T) {
+	l := widget.NewLabel("Header")
+	meta := map[fyne.CanvasObject]map[string]string{}
+
+	var buf bytes.Buffer
+	require.Nil(t, ExportGoTest(l, meta, "header", &buf))
+	code := buf.String()
+	assert.Contains(t, code, "func TestHeaderGUI(t *testing.T) {")
+	assert.Contains(t, code, "test.NewTempWindow(t, newHeaderGUI().makeUI())")
+	assert.Contains(t, code, "w.Resize(fyne.NewSize(400, 300))")
+	assert.Contains(t, code, `test.AssertRendersToMarkup(t, "header.gui.xml", w.Canvas())`)
+	assert.Contains(t, code, `if os.Getenv("DEFYNE_UPDATE_SNAPSHOTS") != "" {`)
+	assert.Contains(t, code, "} else if _, err := os.Stat(snapshot); err != nil {\n\t\tt.Fatalf(")
+	_, err := parser.ParseFile(token.NewFileSet(), "header_gui_test.go", code, 0)
+	assert.Nil(t, err)
+
+	SetTestSize(l, meta, fyne.NewSize(320, 240.5))
+	buf.Reset()
+	require.Nil(t, ExportGoTest(l, meta, "main", &buf))
+	code = buf.String()
+	assert.Contains(t, code, "func TestGUI(t *testing.T) {")
+	assert.Contains(t, code, "test.NewTempWindow(t, newGUI().makeUI())")
+	assert.Contains(t, code, "w.Resize(fyne.NewSize(320, 240.5))")
+}
+
+func TestTestSize_EncodeDecode(t *testing.T) {
+	c := container.NewVBox(widget.NewLabel("Header"))
+	meta := map[fyne.CanvasObject]map[string]string{c: {"layout": "VBox", "dir": "vertical"}}
+	assert.Equal(t, fyne.NewSize(400, 300), TestSize(c, meta))
+
+	SetTestSize(c, meta, fyne.NewSize(640, 480))
+	var buf bytes.Buffer
+	require.Nil(t, EncodeObject(c, meta, &buf))
+	assert.Contains(t, buf.String(), "\"TestSize\": {\n    \"Width\": 640,\n    \"Height\": 480\n  },")
+	assert.Equal(t, 1, strings.Count(buf.String(), "640"))
+
+	obj, meta2, err := DecodeObject(&buf)
+	require.Nil(t, err)
+	assert.Equal(t, fyne.NewSize(640, 480), TestSize(obj, meta2))
+
+	_, _, err = DecodeObject(strings.NewReader(`{"Version": 1, "TestSize": {"Width": "wide"}, "Object": {"Type": "*widget.Separator", "Struct": {}}}`))
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,240 @@
+package gui
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/widget"
+
+	"github.com/fyne-io/defyne/internal/guidefs"
+)
+
+var (
+	resourceType    = reflect.TypeOf((*fyne.Resource)(nil)).Elem()
+	toolbarItemType = reflect.TypeOf((*widget.ToolbarItem)(nil)).Elem()
+	marshalerType   = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+
+	structFieldCache sync.Map // map[reflect.Type][]structField
+)
+
+// objectStruct encodes the exported fields of an object in the same layout as `json.Marshal`.
+// Resources are written as their icon name and toolbar items as their type, without changing the object.
+// Fields that match the defaults, the zero value of the object that the decoder starts from, are left out to keep designs minimal.
+// The skipped fields are stored elsewhere in the node, such as the colors that use a theme color name.
+type objectStruct struct {
+	obj, defaults interface{}
+	skip          map[string]string
+}
+
+func (o *objectStruct) MarshalJSON() ([]byte, error) {
+	return encodeStruct(reflect.ValueOf(o.obj), reflect.ValueOf(o.defaults), o.skip)
+}
+
+type structField struct {
+	name      string
+	index     []int
+	omitEmpty bool
+}
+
+// encodeStruct writes the fields of a struct, leaving out those that encode the same as the field of defaults
+// and those named in skip. Child objects are not written as they are stored in the nodes of the design.
+func encodeStruct(v, defaults reflect.Value, skip map[string]string) ([]byte, error) {
+	for v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return []byte("null"), nil
+		}
+		v = v.Elem()
+	}
+	for defaults.Kind() == reflect.Ptr {
+		defaults = defaults.Elem()
+	}
+	if defaults.IsValid() && defaults.Type() != v.Type() {
+		defaults = reflect.Value{}
+	}
+
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	first := true
+	for _, f := range structFields(v.Type()) {
+		field, ok := fieldByIndex(v, f.index)
+		if !ok || (f.omitEmpty && field.IsZero()) || field.Type() == canvasObjectType {
+			continue
+		}
+		if _, skipped := skip[f.name]; skipped {
+			continue
+		}
+
+		data, err := encodeValue(field)
+		if err != nil {
+			return nil, err
+		}
+		if defaults.IsValid() {
+			if def, ok := fieldByIndex(defaults, f.index); ok {
+				if defData, err := encodeValue(def); err == nil && bytes.Equal(data, defData) {
+					continue
+				}
+			}
+		}
+		if !first {
+			buf.WriteByte(',')
+		}
+		first = false
+
+		name, _ := json.Marshal(f.name)
+		buf.Write(name)
+		buf.WriteByte(':')
+		buf.Write(data)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+func encodeValue(v reflect.Value) ([]byte, error) {
+	switch {
+	case v.Type() == resourceType:
+		if v.IsNil() {
+			return []byte("null"), nil
+		}
+		return json.Marshal(guidefs.IconName(v.Interface().(fyne.Resource)))
+	case v.Type() == toolbarItemType:
+		switch item := v.Interface().(type) {
+		case nil:
+			return []byte("null"), nil
+		case *widget.ToolbarSeparator:
+			return json.Marshal(toolbarItem{Type: "Separator"})
+		case *widget.ToolbarSpacer:
+			return json.Marshal(toolbarItem{Type: "Spacer"})
+		default:
+			return encodeStruct(reflect.ValueOf(item), reflect.Value{}, nil)
+		}
+	case v.Kind() == reflect.Slice && v.Type().Elem() == toolbarItemType:
+		if v.IsNil() {
+			return []byte("null"), nil
+		}
+
+		items := make([]json.RawMessage, v.Len())
+		for i := range items {
+			data, err := encodeValue(v.Index(i))
+			if err != nil {
+				return nil, err
+			}
+			items[i] = data
+		}
+		return json.Marshal(items)
+	case v.Kind() == reflect.Struct && strings.HasPrefix(v.Type().PkgPath(), "fyne.io/") &&
+		!v.Type().Implements(marshalerType):
+		return encodeStruct(v, reflect.Zero(v.Type()), nil) // nested values are decoded from zero, such as a TextStyle
+	}
+
+	return json.Marshal(v.Interface())
+}
+
+// clearStoredFields sets the fields of a struct that `encodeStruct` writes and the decoder restores to their zero value,
+// so that the fields left out of a design are not kept at the values set when the object was created.
+func clearStoredFields(v reflect.Value) {
+	for _, f := range structFields(v.Type()) {
+		if field, ok := fieldByIndex(v, f.index); ok && field.CanSet() && isDecodedType(field.Type()) {
+			field.Set(reflect.Zero(field.Type()))
+		}
+	}
+}
+
+// fieldByIndex returns the nested field, or false if it is inside a nil embedded pointer.
+func fieldByIndex(v reflect.Value, index []int) (reflect.Value, bool) {
+	for i, x := range index {
+		if i > 0 && v.Kind() == reflect.Ptr {
+			if v.IsNil() {
+				return reflect.Value{}, false
+			}
+			v = v.Elem()
+		}
+		v = v.Field(x)
+	}
+	return v, true
+}
+
+// structFields returns the fields that `json.Marshal` would write for a struct type, in the same order.
+func structFields(t reflect.Type) []structField {
+	if cached, ok := structFieldCache.Load(t); ok {
+		return cached.([]structField)
+	}
+
+	type candidate struct {
+		structField
+		tagged bool
+	}
+	var all []candidate
+	var walk func(t reflect.Type, index []int)
+	walk = func(t reflect.Type, index []int) {
+		for i := 0; i < t.NumField(); i++ {
+			f := t.Field(i)
+			tag := f.Tag.Get("json")
+			if tag == "-" {
+				continue
+			}
+			name, opts, _ := strings.Cut(tag, ",")
+			fieldIndex := append(append([]int{}, index...), i)
+
+			ft := f.Type
+			if ft.Kind() == reflect.Ptr {
+				ft = ft.Elem()
+			}
+			if f.Anonymous && name == "" && ft.Kind() == reflect.Struct {
+				walk(ft, fieldIndex)
+				continue
+			}
+			if !f.IsExported() {
+				continue
+			}
+
+			tagged := name != ""
+			if !tagged {
+				name = f.Name
+			}
+			all = append(all, candidate{structField{name: name, index: fieldIndex,
+				omitEmpty: strings.Contains(","+opts+",", ",omitempty,")}, tagged})
+		}
+	}
+	walk(t, nil)
+
+	// apply the Go embedding rules, the shallowest field wins and ambiguous names are dropped
+	var fields []structField
+	for _, c := range all {
+		dominant, ambiguous := true, false
+		for _, other := range all {
+			if other.name != c.name || reflect.DeepEqual(other.index, c.index) {
+				continue
+			}
+			switch {
+			case len(other.index) < len(c.index):
+				dominant = false
+			case len(other.index) == len(c.index):
+				if other.tagged && !c.tagged {
+					dominant = false
+				} else if other.tagged == c.tagged {
+					ambiguous = true
+				}
+			}
+		}
+		if dominant && !ambiguous {
+			fields = append(fields, c.structField)
+		}
+	}
+	sort.SliceStable(fields, func(i, j int) bool {
+		a, b := fields[i].index, fields[j].index
+		for k := 0; k < len(a) && k < len(b); k++ {
+			if a[k] != b[k] {
+				return a[k] < b[k]
+			}
+		}
+		return len(a) < len(b)
+	})
+
+	structFieldCache.Store(t, fields)
+	return fields
+}
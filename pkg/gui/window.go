@@ -0,0 +1,281 @@
+package gui
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"regexp"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/driver/desktop"
+)
+
+// windowKey stores the window of a design as JSON, in the metadata of the root object.
+const windowKey = "window"
+
+// shortcutModifiers maps the modifier names of a shortcut, such as "Shortcut+S", to the Fyne constant names.
+var shortcutModifiers = map[string]string{
+	"Shortcut": "KeyModifierShortcutDefault",
+	"Control":  "KeyModifierControl",
+	"Alt":      "KeyModifierAlt",
+	"Shift":    "KeyModifierShift",
+	"Super":    "KeyModifierSuper",
+}
+
+// keyConstant matches the key names that have a Fyne constant named "Key" followed by the name.
+var keyConstant = regexp.MustCompile(`^([A-Z0-9]|F[0-9]{1,2}|Escape|Return|Tab|Insert|Delete|Home|End|Space)$`)
+
+// Window describes the window that shows a design, it is used to generate a `makeWindow` function.
+type Window struct {
+	Title     string
+	Size      *fyne.Size `json:",omitempty"`
+	FixedSize bool       `json:",omitempty"`
+	Master    bool       `json:",omitempty"`
+	MainMenu  []*Menu    `json:",omitempty"`
+}
+
+// Menu is one menu of the main menu of a window.
+type Menu struct {
+	Label string
+	Items []*MenuItem `json:",omitempty"`
+}
+
+// MenuItem is an item of a menu, it is either a separator, an item that runs an action, or a submenu of Items.
+// The Shortcut is a list of modifiers and a key name separated by "+", such as "Shortcut+Shift+S".
+type MenuItem struct {
+	Label     string      `json:",omitempty"`
+	Separator bool        `json:",omitempty"`
+	Shortcut  string      `json:",omitempty"`
+	Action    string      `json:",omitempty"`
+	Items     []*MenuItem `json:",omitempty"`
+}
+
+// WindowFor returns the window that a design is shown in, or nil if no window has been designed.
+func WindowFor(obj fyne.CanvasObject, meta map[fyne.CanvasObject]map[string]string) *Window {
+	data := meta[obj][windowKey]
+	if data == "" {
+		return nil
+	}
+
+	w := &Window{}
+	if err := json.Unmarshal([]byte(data), w); err != nil {
+		fyne.LogError("Failed to read window of design", err)
+		return nil
+	}
+	return w
+}
+
+// SetWindow sets the window that a design is shown in, the window is stored in the metadata of the root object.
+// Passing nil removes the window design.
+func SetWindow(obj fyne.CanvasObject, meta map[fyne.CanvasObject]map[string]string, w *Window) {
+	props, ok := meta[obj]
+	if !ok {
+		props = make(map[string]string)
+		meta[obj] = props
+	}
+	if w == nil {
+		delete(props, windowKey)
+		return
+	}
+
+	data, err := json.Marshal(w)
+	if err != nil {
+		fyne.LogError("Failed to store window of design", err)
+		return
+	}
+	props[windowKey] = string(data)
+}
+
+// ParseShortcut returns the keyboard shortcut described by a string like "Shortcut+Shift+S".
+func ParseShortcut(s string) (*desktop.CustomShortcut, error) {
+	mods, key, err := shortcutParts(s)
+	if err != nil {
+		return nil, err
+	}
+
+	sc := &desktop.CustomShortcut{KeyName: fyne.KeyName(key)}
+	for _, mod := range mods {
+		switch mod {
+		case "Shortcut":
+			sc.Modifier |= fyne.KeyModifierShortcutDefault
+		case "Control":
+			sc.Modifier |= fyne.KeyModifierControl
+		case "Alt":
+			sc.Modifier |= fyne.KeyModifierAlt
+		case "Shift":
+			sc.Modifier |= fyne.KeyModifierShift
+		case "Super":
+			sc.Modifier |= fyne.KeyModifierSuper
+		}
+	}
+	return sc, nil
+}
+
+func shortcutParts(s string) ([]string, string, error) {
+	parts := strings.Split(s, "+")
+	key := parts[len(parts)-1]
+	if key == "" {
+		return nil, "", errors.New("a shortcut must end with a key name")
+	}
+
+	mods := parts[:len(parts)-1]
+	if len(mods) == 0 {
+		return nil, "", errors.New("a shortcut needs at least one modifier")
+	}
+	for _, mod := range mods {
+		if _, ok := shortcutModifiers[mod]; !ok {
+			return nil, "", fmt.Errorf("unknown modifier %q", mod)
+		}
+	}
+	return mods, key, nil
+}
+
+func (d *decoder) decodeWindow(obj fyne.CanvasObject, m map[string]interface{}) {
+	if DialogFor(obj, d.meta) != nil {
+		d.fail("Window", "a design cannot be shown in both a window and a dialog")
+		return
+	}
+
+	w := &Window{}
+	w.Title, _ = d.stringValue(m["Title"], "Window.Title")
+	if size, ok := d.mapValue(m["Size"], "Window.Size"); ok {
+		w.Size = &fyne.Size{}
+		d.decodeFields(reflect.ValueOf(w.Size).Elem(), size, "Window.Size")
+	}
+	w.FixedSize, _ = d.boolValue(m["FixedSize"], "Window.FixedSize")
+	w.Master, _ = d.boolValue(m["Master"], "Window.Master")
+	if menus, ok := d.sliceValue(m["MainMenu"], "Window.MainMenu"); ok {
+		for i, v := range menus {
+			path := fmt.Sprintf("Window.MainMenu[%d]", i)
+			menu, ok := d.mapValue(v, path)
+			if !ok {
+				continue
+			}
+
+			label, _ := d.stringValue(menu["Label"], joinPath(path, "Label"))
+			w.MainMenu = append(w.MainMenu, &Menu{Label: label, Items: d.decodeMenuItems(menu["Items"], joinPath(path, "Items"))})
+		}
+	}
+	SetWindow(obj, d.meta, w)
+}
+
+func (d *decoder) decodeMenuItems(v interface{}, path string) []*MenuItem {
+	list, ok := d.sliceValue(v, path)
+	if !ok {
+		return nil
+	}
+
+	items := make([]*MenuItem, 0, len(list))
+	for i, v := range list {
+		itemPath := fmt.Sprintf("%s[%d]", path, i)
+		m, ok := d.mapValue(v, itemPath)
+		if !ok {
+			continue
+		}
+
+		item := &MenuItem{}
+		item.Label, _ = d.stringValue(m["Label"], joinPath(itemPath, "Label"))
+		item.Separator, _ = d.boolValue(m["Separator"], joinPath(itemPath, "Separator"))
+		item.Action, _ = d.stringValue(m["Action"], joinPath(itemPath, "Action"))
+		if sc, ok := d.stringValue(m["Shortcut"], joinPath(itemPath, "Shortcut")); ok {
+			if _, _, err := shortcutParts(sc); err != nil {
+				d.fail(joinPath(itemPath, "Shortcut"), "invalid shortcut %q: %s", sc, err)
+			} else {
+				item.Shortcut = sc
+			}
+		}
+		item.Items = d.decodeMenuItems(m["Items"], joinPath(itemPath, "Items"))
+		items = append(items, item)
+	}
+	return items
+}
+
+// windowPackages returns the packages that the window of a design needs, beyond those of its objects.
+func windowPackages(w *Window) []string {
+	var uses func([]*MenuItem) bool
+	uses = func(items []*MenuItem) bool {
+		for _, item := range items {
+			if item.Shortcut != "" || uses(item.Items) {
+				return true
+			}
+		}
+		return false
+	}
+	for _, menu := range w.MainMenu {
+		if uses(menu.Items) {
+			return []string{"driver/desktop"}
+		}
+	}
+	return nil
+}
+
+// windowGoString returns the Go code of a `makeWindow(app fyne.App) fyne.Window` method of the gui type.
+func windowGoString(w *Window, guiName string) string {
+	code := &strings.Builder{}
+	fmt.Fprintf(code, `
+// makeWindow creates a window that shows the design, with the title, size and main menu that it was designed with.
+func (g *%s) makeWindow(app fyne.App) fyne.Window {
+	w := app.NewWindow(%q)
+	w.SetContent(g.makeUI())
+`, guiName, w.Title)
+	if w.Size != nil {
+		fmt.Fprintf(code, "\tw.Resize(fyne.NewSize(%#v, %#v))\n", w.Size.Width, w.Size.Height)
+	}
+	if w.FixedSize {
+		code.WriteString("\tw.SetFixedSize(true)\n")
+	}
+	if w.Master {
+		code.WriteString("\tw.SetMaster()\n")
+	}
+	if len(w.MainMenu) > 0 {
+		code.WriteString("\tw.SetMainMenu(fyne.NewMainMenu(\n")
+		for _, menu := range w.MainMenu {
+			fmt.Fprintf(code, "fyne.NewMenu(%q,\n%s),\n", menu.Label, menuItemsGoString(menu.Items))
+		}
+		code.WriteString("))\n")
+	}
+	code.WriteString("\treturn w\n}\n")
+	return code.String()
+}
+
+func menuItemsGoString(items []*MenuItem) string {
+	code := &strings.Builder{}
+	for _, item := range items {
+		if item.Separator {
+			code.WriteString("fyne.NewMenuItemSeparator(),\n")
+			continue
+		}
+
+		fmt.Fprintf(code, "&fyne.MenuItem{Label: %q", item.Label)
+		if item.Shortcut != "" {
+			code.WriteString(", Shortcut: " + shortcutGoString(item.Shortcut))
+		}
+		if item.Action != "" {
+			code.WriteString(", Action: " + item.Action)
+		}
+		if len(item.Items) > 0 {
+			fmt.Fprintf(code, ", ChildMenu: fyne.NewMenu(\"\",\n%s)", menuItemsGoString(item.Items))
+		}
+		code.WriteString("},\n")
+	}
+	return code.String()
+}
+
+func shortcutGoString(s string) string {
+	mods, key, err := shortcutParts(s)
+	if err != nil {
+		return "nil"
+	}
+
+	names := make([]string, len(mods))
+	for i, mod := range mods {
+		names[i] = "fyne." + shortcutModifiers[mod]
+	}
+	keyName := fmt.Sprintf("fyne.KeyName(%q)", key)
+	if keyConstant.MatchString(key) {
+		keyName = "fyne.Key" + key
+	}
+	return fmt.Sprintf("&desktop.CustomShortcut{KeyName: %s, Modifier: %s}", keyName, strings.Join(names, " | "))
+}
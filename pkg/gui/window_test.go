@@ -0,0 +1,111 @@
+package gui
+
+import (
+	"bytes"
+	"errors"
+	"go/parser"
+	"go/token"
+	"strings"
+	"testing"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/widget"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func windowDesign() (fyne.CanvasObject, map[fyne.CanvasObject]map[string]string) {
+	l := widget.NewLabel("Editor")
+	meta := map[fyne.CanvasObject]map[string]string{}
+	SetWindow(l, meta, &Window{Title: "Notes", Size: &fyne.Size{Width: 800, Height: 600}, Master: true,
+		MainMenu: []*Menu{{Label: "File", Items: []*MenuItem{
+			{Label: "Save", Shortcut: "Shortcut+S", Action: "g.save"},
+			{Separator: true},
+			{Label: "Recent", Items: []*MenuItem{{Label: "notes.txt", Action: "func() {}"}}},
+		}}}})
+	return l, meta
+}
+
+func TestWindow_EncodeDecode(t *testing.T) {
+	obj, meta := windowDesign()
+
+	var buf bytes.Buffer
+	require.Nil(t, EncodeObject(obj, meta, &buf))
+	assert.Contains(t, buf.String(), `"Window": {`)
+	assert.NotContains(t, buf.String(), `"window"`)
+
+	obj2, meta2, err := DecodeObject(&buf)
+	require.Nil(t, err)
+	win := WindowFor(obj2, meta2)
+	require.NotNil(t, win)
+	assert.Equal(t, WindowFor(obj, meta), win)
+	assert.Equal(t, "notes.txt", win.MainMenu[0].Items[2].Items[0].Label)
+
+	SetWindow(obj2, meta2, nil)
+	assert.Nil(t, WindowFor(obj2, meta2))
+}
+
+func TestWindow_DecodeErrors(t *testing.T) {
+	for doc, problem := range map[string]DecodeProblem{
+		`{"Version": 1, "Window": {"MainMenu": [{"Label": "File", "Items": [{"Label": "Save", "Shortcut": "Hyper+S"}]}]}, "Object": {"Type": "*widget.Label", "Struct": {}}}`: {
+			Path: "Window.MainMenu[0].Items[0].Shortcut", Message: `invalid shortcut "Hyper+S": unknown modifier "Hyper"`},
+		`{"Version": 1, "Window": {"Title": 5}, "Object": {"Type": "*widget.Label", "Struct": {}}}`: {
+			Path: "Window.Title", Message: "expected a string, found number"},
+		`{"Version": 1, "Dialog": {"Type": "Custom"}, "Window": {}, "Object": {"Type": "*widget.Label", "Struct": {}}}`: {
+			Path: "Window", Message: "a design cannot be shown in both a window and a dialog"},
+	} {
+		_, _, err := DecodeObject(strings.NewReader(doc))
+		var decodeErr *DecodeError
+		require.True(t, errors.As(err, &decodeErr), doc)
+		assert.Equal(t, []DecodeProblem{problem}, decodeErr.Problems)
+	}
+}
+
+func TestWindow_ExportGo(t *testing.T) {
+	obj, meta := windowDesign()
+
+	var buf bytes.Buffer
+	require.Nil(t, ExportGoPreview(obj, meta, &buf))
+	_, err := parser.ParseFile(token.NewFileSet(), "main.go", buf.Bytes(), 0)
+	require.Nil(t, err)
+	code := strings.Join(strings.Fields(buf.String()), " ")
+	assert.Contains(t, code, `"fyne.io/fyne/v2/driver/desktop"`)
+	assert.Contains(t, code, "func (g *gui) makeWindow(app fyne.App) fyne.Window {")
+	assert.Contains(t, code, `w := app.NewWindow("Notes")`)
+	assert.Contains(t, code, "w.Resize(fyne.NewSize(800, 600))")
+	assert.Contains(t, code, "w.SetMaster()")
+	assert.NotContains(t, code, "SetFixedSize")
+	assert.Contains(t, code, `fyne.NewMenu("File", &fyne.MenuItem{Label: "Save", `+
+		`Shortcut: &desktop.CustomShortcut{KeyName: fyne.KeyS, Modifier: fyne.KeyModifierShortcutDefault}, Action: g.save}, `+
+		`fyne.NewMenuItemSeparator(), &fyne.MenuItem{Label: "Recent", ChildMenu: fyne.NewMenu("", `+
+		`&fyne.MenuItem{Label: "notes.txt", Action: func() {}}, )}, ),`)
+	assert.Contains(t, code, "myWindow := newGUI().makeWindow(myApp)")
+}
+
+func TestParseShortcut(t *testing.T) {
+	sc, err := ParseShortcut("Alt+Shift+F4")
+	require.Nil(t, err)
+	assert.Equal(t, fyne.KeyF4, sc.KeyName)
+	assert.Equal(t, fyne.KeyModifierAlt|fyne.KeyModifierShift, sc.Modifier)
+	assert.Equal(t, "&desktop.CustomShortcut{KeyName: fyne.KeyName(\"/\"), Modifier: fyne.KeyModifierControl}",
+		shortcutGoString("Control+/"))
+
+	for _, s := range []string{"S", "Shortcut+", "Meta+S"} {
+		_, err = ParseShortcut(s)
+		assert.NotNil(t, err, s)
+	}
+}
+
+func TestWindow_Schema(t *testing.T) {
+	schema := loadSchema(t)
+	obj, meta := windowDesign()
+
+	var buf bytes.Buffer
+	require.Nil(t, EncodeObject(obj, meta, &buf))
+	assert.Empty(t, validateSchema(schema, decodeJSON(t, buf.Bytes())))
+
+	problems := validateSchema(schema, decodeJSON(t, []byte(
+		`{"Version": 1, "Window": {"MainMenu": [{"Items": [{"Shortcut": "S"}]}]}, "Object": {"Type": "*widget.Label", "Struct": {}}}`)))
+	assert.Contains(t, strings.Join(problems, "\n"), "/Window/MainMenu/0/Items/0/Shortcut: does not match")
+}
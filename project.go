@@ -169,30 +169,19 @@ func (g *gui) makeUI() fyne.CanvasObject {
 		return dir, nil
 	}
 	err = writeFile(dir, "main.gui.json", `{
-  "Type": "*fyne.Container",
-  "Layout": "VBox",
-  "Name": "",
-  "Objects": [
-    {
-      "Type": "*widget.Label",
-      "Name": "",
-      "Struct": {
-        "Hidden": false,
-        "Text": "Hello `+name+`!",
-        "Alignment": 0,
-        "Wrapping": 0,
-        "TextStyle": {
-          "Bold": false,
-          "Italic": false,
-          "Monospace": false,
-          "Symbol": false,
-          "TabWidth": 0
-        },
-        "Truncation": 0,
-        "Importance": 0
+  "Version": 3,
+  "Object": {
+    "Type": "*fyne.Container",
+    "Layout": "VBox",
+    "Objects": [
+      {
+        "Type": "*widget.Label",
+        "Struct": {
+          "Text": "Hello `+name+`!"
+        }
       }
-    }
-  ]
+    ]
+  }
 }
 `)
 	if err != nil {